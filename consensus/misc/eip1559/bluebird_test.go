@@ -17,10 +17,14 @@
 package eip1559
 
 import (
+	"math"
 	"math/big"
+	"strings"
 	"testing"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -160,16 +164,10 @@ func TestBluebirdEIP1559Parameters(t *testing.T) {
 
 		// Calculate base fee increase percentage before and after Bluebird
 		beforeTime := uint64(999)
-		baseFeeBeforeBluebird := CalcBaseFee(config, parent, beforeTime)
-		percentIncreaseBeforeBluebird := new(big.Int).Sub(baseFeeBeforeBluebird, parent.BaseFee)
-		percentIncreaseBeforeBluebird.Mul(percentIncreaseBeforeBluebird, big.NewInt(100))
-		percentIncreaseBeforeBluebird.Div(percentIncreaseBeforeBluebird, parent.BaseFee)
+		percentIncreaseBeforeBluebird := BaseFeeDeltaPercent(config, parent, beforeTime)
 
 		afterTime := uint64(1001)
-		baseFeeAfterBluebird := CalcBaseFee(config, parent, afterTime)
-		percentIncreaseAfterBluebird := new(big.Int).Sub(baseFeeAfterBluebird, parent.BaseFee)
-		percentIncreaseAfterBluebird.Mul(percentIncreaseAfterBluebird, big.NewInt(100))
-		percentIncreaseAfterBluebird.Div(percentIncreaseAfterBluebird, parent.BaseFee)
+		percentIncreaseAfterBluebird := BaseFeeDeltaPercent(config, parent, afterTime)
 
 		t.Logf("Percent increase before Bluebird: %s%%", percentIncreaseBeforeBluebird)
 		t.Logf("Percent increase after Bluebird: %s%%", percentIncreaseAfterBluebird)
@@ -183,4 +181,669 @@ func TestBluebirdEIP1559Parameters(t *testing.T) {
 			t.Errorf("Base fee denominator not correctly applied")
 		}
 	})
-}
\ No newline at end of file
+}
+// bluebirdConfig returns a London-enabled test config with Bluebird activating
+// at the given timestamp.
+func bluebirdConfig(bluebirdTime uint64) *params.ChainConfig {
+	config := copyConfig(params.TestChainConfig)
+	config.LondonBlock = big.NewInt(0)
+	config.BluebirdTime = &bluebirdTime
+	return config
+}
+
+func TestProjectBaseFee(t *testing.T) {
+	config := bluebirdConfig(1000)
+	parent := &types.Header{
+		Number:   big.NewInt(1),
+		Time:     998,
+		GasLimit: 30_000_000,
+		GasUsed:  30_000_000,
+		BaseFee:  big.NewInt(1_000_000_000),
+	}
+
+	// Full blocks must strictly increase the base fee at every step, including
+	// across the Bluebird activation.
+	fees := ProjectBaseFee(config, parent, 996, 10, parent.GasLimit)
+	if len(fees) != 10 {
+		t.Fatalf("projection length mismatch: have %d, want %d", len(fees), 10)
+	}
+	prev := parent.BaseFee
+	for i, fee := range fees {
+		if fee.Cmp(prev) <= 0 {
+			t.Errorf("step %d: base fee did not increase: have %s, prev %s", i, fee, prev)
+		}
+		prev = fee
+	}
+
+	// Empty blocks must converge to, and never drop below, the Bluebird minimum.
+	minBaseFee := new(big.Int).SetUint64(params.BluebirdMinBaseFee)
+	parent.GasUsed = 0
+	fees = ProjectBaseFee(config, parent, 1000, 200, 0)
+	for i, fee := range fees {
+		if fee.Cmp(minBaseFee) < 0 {
+			t.Fatalf("step %d: base fee %s below Bluebird minimum %s", i, fee, minBaseFee)
+		}
+		if i > 0 && fee.Cmp(fees[i-1]) > 0 {
+			t.Errorf("step %d: base fee increased on empty block: have %s, prev %s", i, fee, fees[i-1])
+		}
+	}
+	if last := fees[len(fees)-1]; last.Cmp(minBaseFee) != 0 {
+		t.Errorf("projection did not converge to minimum: have %s, want %s", last, minBaseFee)
+	}
+
+	// The projection must match iterated CalcBaseFee calls at every step.
+	parent.GasUsed = 20_000_000
+	fees = ProjectBaseFee(config, parent, 996, 5, 20_000_000)
+	head := parent
+	for i, ts := 0, uint64(996); i < len(fees); i, ts = i+1, ts+projectedBlockInterval {
+		want := CalcBaseFee(config, head, ts)
+		if fees[i].Cmp(want) != 0 {
+			t.Errorf("step %d: projection mismatch: have %s, want %s", i, fees[i], want)
+		}
+		head = &types.Header{Number: new(big.Int).Add(head.Number, common.Big1), GasLimit: head.GasLimit, GasUsed: 20_000_000, BaseFee: want}
+	}
+	if fees := ProjectBaseFee(config, parent, 1000, 0, 0); fees != nil {
+		t.Errorf("expected nil projection for zero blocks, got %v", fees)
+	}
+}
+
+func TestCalcBaseFeeFloorDecay(t *testing.T) {
+	var (
+		target = uint64(200_000)
+		window = uint64(800)
+	)
+	config := bluebirdConfig(1000)
+	config.BluebirdFloorDecayTarget = &target
+	config.BluebirdFloorDecayWindow = window
+
+	// An empty parent with a tiny base fee always lands on the floor
+	parent := &types.Header{
+		Number:   big.NewInt(1),
+		GasLimit: 30_000_000,
+		GasUsed:  0,
+		BaseFee:  big.NewInt(1),
+	}
+	for _, tc := range []struct {
+		name string
+		time uint64
+		want uint64
+	}{
+		{"start", 1000, params.BluebirdMinBaseFee},
+		{"middle", 1000 + window/2, (params.BluebirdMinBaseFee + target) / 2},
+		{"end", 1000 + window, target},
+	} {
+		if have := CalcBaseFee(config, parent, tc.time); have.Uint64() != tc.want {
+			t.Errorf("%s: floor mismatch: have %d, want %d", tc.name, have, tc.want)
+		}
+	}
+}
+
+func TestCalcBaseFeeAtBluebirdTarget(t *testing.T) {
+	config := bluebirdConfig(1000)
+
+	// Gas limits both divisible and not divisible by the elasticity multiplier
+	for _, gasLimit := range []uint64{30_000_000, 30_000_002} {
+		parent := &types.Header{
+			Number:   big.NewInt(1),
+			GasLimit: gasLimit,
+			GasUsed:  gasLimit / params.BluebirdElasticityMultiplier,
+			BaseFee:  big.NewInt(1_000_000_000),
+		}
+		if have := CalcBaseFee(config, parent, 1001); have.Cmp(parent.BaseFee) != 0 {
+			t.Errorf("gas limit %d: base fee changed at Bluebird target: have %s, want %s", gasLimit, have, parent.BaseFee)
+		}
+		// One gas off the target must move the base fee in either direction
+		parent.GasUsed++
+		if have := CalcBaseFee(config, parent, 1001); have.Cmp(parent.BaseFee) <= 0 {
+			t.Errorf("gas limit %d: base fee did not increase above target: have %s", gasLimit, have)
+		}
+		parent.GasUsed -= 2
+		if have := CalcBaseFee(config, parent, 1001); have.Cmp(parent.BaseFee) > 0 {
+			t.Errorf("gas limit %d: base fee increased below target: have %s", gasLimit, have)
+		}
+	}
+}
+
+func TestCalcBaseFeeWithFlag(t *testing.T) {
+	config := bluebirdConfig(1000)
+	minBaseFee := new(big.Int).SetUint64(params.BluebirdMinBaseFee)
+
+	for _, tc := range []struct {
+		name    string
+		gasUsed uint64
+		baseFee int64
+		time    uint64
+		clamped bool
+	}{
+		{"clamped decrease", 0, int64(params.BluebirdMinBaseFee), 1001, true},
+		{"unclamped decrease", 0, 1_000_000_000, 1001, false},
+		{"increase", 30_000_000, 1000, 1001, false},
+		{"decrease before Bluebird", 0, 1000, 999, false},
+	} {
+		parent := &types.Header{
+			Number:   big.NewInt(1),
+			GasLimit: 30_000_000,
+			GasUsed:  tc.gasUsed,
+			BaseFee:  big.NewInt(tc.baseFee),
+		}
+		baseFee, clamped := CalcBaseFeeWithFlag(config, parent, tc.time)
+		if clamped != tc.clamped {
+			t.Errorf("%s: clamp flag mismatch: have %v, want %v", tc.name, clamped, tc.clamped)
+		}
+		if clamped && baseFee.Cmp(minBaseFee) != 0 {
+			t.Errorf("%s: clamped base fee mismatch: have %s, want %s", tc.name, baseFee, minBaseFee)
+		}
+		if want := CalcBaseFee(config, parent, tc.time); baseFee.Cmp(want) != 0 {
+			t.Errorf("%s: base fee differs from CalcBaseFee: have %s, want %s", tc.name, baseFee, want)
+		}
+	}
+}
+
+func TestBaseFeeParamsForBlock(t *testing.T) {
+	config := bluebirdConfig(1000)
+
+	for _, tc := range []struct {
+		time        uint64
+		elasticity  uint64
+		denominator uint64
+		bluebird    bool
+	}{
+		{999, params.DefaultElasticityMultiplier, params.DefaultBaseFeeChangeDenominator, false},
+		{1000, params.BluebirdElasticityMultiplier, params.BluebirdBaseFeeChangeDenominator, true},
+		{1001, params.BluebirdElasticityMultiplier, params.BluebirdBaseFeeChangeDenominator, true},
+	} {
+		header := &types.Header{Number: big.NewInt(1), Time: tc.time}
+		elasticity, denominator, bluebird := BaseFeeParamsForBlock(config, header)
+		if elasticity != tc.elasticity || denominator != tc.denominator || bluebird != tc.bluebird {
+			t.Errorf("time %d: have (%d, %d, %v), want (%d, %d, %v)", tc.time,
+				elasticity, denominator, bluebird, tc.elasticity, tc.denominator, tc.bluebird)
+		}
+	}
+}
+
+func TestCalcBaseFeeLargeGas(t *testing.T) {
+	config := bluebirdConfig(0)
+	var (
+		gasLimit = uint64(math.MaxUint64 / 2)
+		target   = gasLimit / params.BluebirdElasticityMultiplier
+		baseFee  = new(big.Int).Lsh(big.NewInt(1), 200)
+		denom    = new(big.Int).SetUint64(params.BluebirdBaseFeeChangeDenominator)
+	)
+	// A full block raises the base fee by (elasticity-1)/denominator at most
+	parent := &types.Header{Number: big.NewInt(1), GasLimit: gasLimit, GasUsed: gasLimit, BaseFee: baseFee}
+	want := new(big.Int).SetUint64(gasLimit - target)
+	want.Mul(want, baseFee)
+	want.Div(want, new(big.Int).SetUint64(target))
+	want.Div(want, denom)
+	want.Add(want, baseFee)
+	if have := CalcBaseFee(config, parent, 2); have.Cmp(want) != 0 {
+		t.Errorf("full block: have %s, want %s", have, want)
+	}
+	if have := CalcBaseFee(config, parent, 2); have.Cmp(baseFee) <= 0 {
+		t.Errorf("full block: base fee did not increase: %s", have)
+	}
+
+	// An empty block lowers the base fee by exactly 1/denominator
+	parent.GasUsed = 0
+	want = new(big.Int).Sub(baseFee, new(big.Int).Div(baseFee, denom))
+	if have := CalcBaseFee(config, parent, 2); have.Cmp(want) != 0 {
+		t.Errorf("empty block: have %s, want %s", have, want)
+	}
+}
+
+func TestCalcBaseFeeDenominatorOverride(t *testing.T) {
+	config := bluebirdConfig(1000)
+	denom := uint64(16)
+	config.BluebirdBaseFeeChangeDenominatorOverride = &denom
+
+	// An empty block lowers the base fee by 1/16 rather than 1/8
+	parent := &types.Header{
+		Number:   big.NewInt(1),
+		GasLimit: 30_000_000,
+		GasUsed:  0,
+		BaseFee:  big.NewInt(1_600_000_000),
+	}
+	if have, want := CalcBaseFee(config, parent, 1001), big.NewInt(1_500_000_000); have.Cmp(want) != 0 {
+		t.Errorf("base fee mismatch: have %s, want %s", have, want)
+	}
+	// Before Bluebird the override has no effect
+	if have, want := CalcBaseFee(config, parent, 999), big.NewInt(1_400_000_000); have.Cmp(want) != 0 {
+		t.Errorf("pre-Bluebird base fee mismatch: have %s, want %s", have, want)
+	}
+}
+
+func TestBaseFeeClampedCounter(t *testing.T) {
+	defer func(old metrics.Counter) { baseFeeClampedCounter = old }(baseFeeClampedCounter)
+	baseFeeClampedCounter = metrics.NewCounterForced()
+
+	config := bluebirdConfig(1000)
+	parent := &types.Header{
+		Number:   big.NewInt(1),
+		GasLimit: 30_000_000,
+		GasUsed:  0,
+		BaseFee:  new(big.Int).SetUint64(params.BluebirdMinBaseFee),
+	}
+	verify := func(time uint64) {
+		t.Helper()
+		header := &types.Header{
+			Number:   big.NewInt(2),
+			Time:     time,
+			GasLimit: parent.GasLimit,
+			BaseFee:  CalcBaseFee(config, parent, time),
+		}
+		if err := VerifyEIP1559Header(config, parent, header); err != nil {
+			t.Fatalf("failed to verify header: %v", err)
+		}
+	}
+	// Verified empty blocks at the floor are clamped, the result is unchanged
+	for i := 0; i < 3; i++ {
+		verify(1001)
+	}
+	// Other calculations, and unclamped verifications, are not counted
+	if baseFee := CalcBaseFee(config, parent, 1001); baseFee.Uint64() != params.BluebirdMinBaseFee {
+		t.Fatalf("clamped base fee mismatch: have %s, want %d", baseFee, params.BluebirdMinBaseFee)
+	}
+	ProjectBaseFee(config, parent, 1001, 5, 0)
+	CalcBaseFeeWithParams(parent, params.BluebirdElasticityMultiplier, params.BluebirdBaseFeeChangeDenominator, params.BluebirdMinBaseFee)
+	verify(999)
+	parent.GasUsed = parent.GasLimit
+	verify(1001)
+
+	if have := baseFeeClampedCounter.Snapshot().Count(); have != 3 {
+		t.Errorf("clamp count mismatch: have %d, want 3", have)
+	}
+}
+
+func TestBaseFeeDeltaPercent(t *testing.T) {
+	config := bluebirdConfig(1000)
+
+	for _, tc := range []struct {
+		name    string
+		gasUsed uint64
+		time    uint64
+		want    int64
+	}{
+		{"full block before Bluebird", 30_000_000, 999, 12}, // 1/8 of (2x - 1x) / 1x
+		{"full block after Bluebird", 30_000_000, 1001, 25}, // 1/8 of (3x - 1x) / 1x
+		{"empty block before Bluebird", 0, 999, -12},
+		{"empty block after Bluebird", 0, 1001, -12},
+	} {
+		parent := &types.Header{
+			Number:   big.NewInt(1),
+			GasLimit: 30_000_000,
+			GasUsed:  tc.gasUsed,
+			BaseFee:  big.NewInt(1_000_000_000),
+		}
+		if have := BaseFeeDeltaPercent(config, parent, tc.time); have == nil || have.Int64() != tc.want {
+			t.Errorf("%s: percent change mismatch: have %v, want %d", tc.name, have, tc.want)
+		}
+	}
+	// Parents without a base fee have nothing to compare against
+	if have := BaseFeeDeltaPercent(config, &types.Header{Number: big.NewInt(1)}, 1001); have != nil {
+		t.Errorf("missing parent base fee: have %v, want nil", have)
+	}
+}
+
+func TestVerifyEIP1559HeaderBluebird(t *testing.T) {
+	config := bluebirdConfig(1000)
+	parent := &types.Header{
+		Number:   big.NewInt(1),
+		Time:     998,
+		GasLimit: 30_000_000,
+		GasUsed:  30_000_000,
+		BaseFee:  big.NewInt(1_000_000_000),
+	}
+	for _, time := range []uint64{999, 1000, 1001} {
+		header := &types.Header{
+			Number:   big.NewInt(2),
+			Time:     time,
+			GasLimit: parent.GasLimit,
+			BaseFee:  CalcBaseFee(config, parent, time),
+		}
+		if err := VerifyEIP1559Header(config, parent, header); err != nil {
+			t.Errorf("time %d: valid header rejected: %v", time, err)
+		}
+		// A base fee computed under the wrong fork rules must be rejected
+		want := header.BaseFee
+		header.BaseFee = new(big.Int).Add(want, common.Big1)
+		err := VerifyEIP1559Header(config, parent, header)
+		if err == nil {
+			t.Fatalf("time %d: tampered base fee accepted", time)
+		}
+		if msg := err.Error(); !strings.Contains(msg, "have "+header.BaseFee.String()) || !strings.Contains(msg, "want "+want.String()) {
+			t.Errorf("time %d: error does not report expected and actual base fee: %v", time, err)
+		}
+	}
+	// Pre- and post-Bluebird rules produce different results for a full block,
+	// so a header computed under the wrong rules must fail verification.
+	header := &types.Header{
+		Number:   big.NewInt(2),
+		Time:     1000,
+		GasLimit: parent.GasLimit,
+		BaseFee:  CalcBaseFee(config, parent, 999),
+	}
+	if err := VerifyEIP1559Header(config, parent, header); err == nil {
+		t.Errorf("pre-Bluebird base fee accepted on Bluebird header")
+	}
+}
+
+func TestIsBluebirdTransitionBlock(t *testing.T) {
+	config := bluebirdConfig(1000)
+
+	for _, tc := range []struct {
+		name         string
+		parent, time uint64
+		want         bool
+	}{
+		{"exact transition", 998, 1000, true},
+		{"transition after activation", 998, 1002, true},
+		{"mid-fork", 1000, 1002, false},
+		{"pre-fork", 996, 998, false},
+	} {
+		parent := &types.Header{Number: big.NewInt(1), Time: tc.parent}
+		header := &types.Header{Number: big.NewInt(2), Time: tc.time}
+		if have := IsBluebirdTransitionBlock(config, parent, header); have != tc.want {
+			t.Errorf("%s: have %v, want %v", tc.name, have, tc.want)
+		}
+	}
+	// Chains without Bluebird never transition
+	parent := &types.Header{Number: big.NewInt(1), Time: 998}
+	header := &types.Header{Number: big.NewInt(2), Time: 1000}
+	if IsBluebirdTransitionBlock(params.TestChainConfig, parent, header) {
+		t.Errorf("transition detected without Bluebird configured")
+	}
+}
+
+func TestCalcBaseFeeOneGasAboveBluebirdTarget(t *testing.T) {
+	config := bluebirdConfig(1000)
+	const gasLimit = 30_000_000 // Bluebird target of 10M gas
+
+	for _, tc := range []struct {
+		name    string
+		baseFee int64
+		want    int64
+	}{
+		// 1e6 * 1 / 10M / 8 truncates to zero, the minimum 1 wei increase applies
+		{"minimum increase", 1_000_000, 1_000_001},
+		// 8e8 * 1 / 10M / 8 = 10 exactly
+		{"exact delta", 800_000_000, 800_000_010},
+		// 879_999_999 * 1 / 10M / 8 = 10.99..., floored to 10
+		{"floored delta", 879_999_999, 880_000_009},
+	} {
+		parent := &types.Header{
+			Number:   big.NewInt(1),
+			GasLimit: gasLimit,
+			GasUsed:  gasLimit/params.BluebirdElasticityMultiplier + 1,
+			BaseFee:  big.NewInt(tc.baseFee),
+		}
+		if have := CalcBaseFee(config, parent, 1001); have.Int64() != tc.want {
+			t.Errorf("%s: base fee mismatch: have %s, want %d", tc.name, have, tc.want)
+		}
+	}
+}
+
+func TestCalcBaseFeeBluebirdActivationBlock(t *testing.T) {
+	config := bluebirdConfig(1000)
+	parent := &types.Header{
+		Number:   big.NewInt(1),
+		Time:     998, // built under pre-Bluebird rules
+		GasLimit: 30_000_000,
+		GasUsed:  30_000_000,
+		BaseFee:  big.NewInt(1_000_000_000),
+	}
+	// A full block exceeds the Bluebird target of 10M gas by 20M, raising the base
+	// fee by 2/8, whereas the pre-Bluebird target of 15M would only give 1/8.
+	if have, want := CalcBaseFee(config, parent, 1000), big.NewInt(1_250_000_000); have.Cmp(want) != 0 {
+		t.Errorf("activation block base fee mismatch: have %s, want %s", have, want)
+	}
+	if have, want := CalcBaseFee(config, parent, 999), big.NewInt(1_125_000_000); have.Cmp(want) != 0 {
+		t.Errorf("pre-activation base fee mismatch: have %s, want %s", have, want)
+	}
+	// The Bluebird floor applies from the activation block too
+	parent.GasUsed = 0
+	parent.BaseFee = big.NewInt(1)
+	if have := CalcBaseFee(config, parent, 1000); have.Uint64() != params.BluebirdMinBaseFee {
+		t.Errorf("activation block floor mismatch: have %s, want %d", have, params.BluebirdMinBaseFee)
+	}
+}
+
+func TestCalcBaseFeeAtTargetFastPath(t *testing.T) {
+	config := bluebirdConfig(1000)
+	for _, tc := range []struct {
+		time       uint64
+		elasticity uint64
+	}{
+		{999, params.DefaultElasticityMultiplier},
+		{1001, params.BluebirdElasticityMultiplier},
+	} {
+		parent := &types.Header{
+			Number:   big.NewInt(1),
+			GasLimit: 30_000_000,
+			GasUsed:  30_000_000 / tc.elasticity,
+			BaseFee:  big.NewInt(1_000_000_000),
+		}
+		// The full computation with a zero gas delta leaves the base fee as is
+		full := new(big.Int).Set(parent.BaseFee)
+		have := CalcBaseFee(config, parent, tc.time)
+		if have.Cmp(full) != 0 {
+			t.Errorf("time %d: fast path mismatch: have %s, want %s", tc.time, have, full)
+		}
+		if have == parent.BaseFee {
+			t.Errorf("time %d: fast path aliases the parent base fee", tc.time)
+		}
+	}
+}
+
+// TestCalcBaseFeeEmptyBlocksFloor runs a long series of empty Bluebird blocks and
+// checks that the base fee decays monotonically onto the minimum, never dipping
+// below it on the way.
+func TestCalcBaseFeeEmptyBlocksFloor(t *testing.T) {
+	config := bluebirdConfig(0)
+	head := &types.Header{
+		Number:   big.NewInt(1),
+		Time:     1,
+		GasLimit: 30_000_000,
+		BaseFee:  big.NewInt(1_000_000_000),
+	}
+	minBaseFee := new(big.Int).SetUint64(params.BluebirdMinBaseFee)
+	for i := 0; i < 100; i++ {
+		baseFee := CalcBaseFee(config, head, head.Time+2)
+		if baseFee.Cmp(minBaseFee) < 0 {
+			t.Fatalf("block %d: base fee %s below minimum %s", i, baseFee, minBaseFee)
+		}
+		if baseFee.Cmp(head.BaseFee) > 0 {
+			t.Fatalf("block %d: base fee increased from %s to %s", i, head.BaseFee, baseFee)
+		}
+		head = &types.Header{
+			Number:   new(big.Int).Add(head.Number, common.Big1),
+			Time:     head.Time + 2,
+			GasLimit: head.GasLimit,
+			BaseFee:  baseFee,
+		}
+	}
+	if head.BaseFee.Cmp(minBaseFee) != 0 {
+		t.Errorf("final base fee mismatch: have %s, want %s", head.BaseFee, minBaseFee)
+	}
+}
+
+func TestCalcBaseFeeWithParams(t *testing.T) {
+	config := bluebirdConfig(0)
+	for _, gasUsed := range []uint64{0, 1_000_000, 10_000_000, 15_000_000, 30_000_000} {
+		for _, baseFee := range []int64{1_000_000, 1_000_001, 1_000_000_000} {
+			parent := &types.Header{
+				Number:   big.NewInt(1),
+				GasLimit: 30_000_000,
+				GasUsed:  gasUsed,
+				BaseFee:  big.NewInt(baseFee),
+			}
+			want := CalcBaseFee(config, parent, 2)
+			have := CalcBaseFeeWithParams(parent, params.BluebirdElasticityMultiplier, params.BluebirdBaseFeeChangeDenominator, params.BluebirdMinBaseFee)
+			if have.Cmp(want) != 0 {
+				t.Errorf("gas used %d, base fee %d: mismatch: have %s, want %s", gasUsed, baseFee, have, want)
+			}
+		}
+	}
+	// Custom parameters are honoured: an elasticity of 4 targets 7.5M gas, and a
+	// zero minimum lets empty blocks decay freely
+	parent := &types.Header{Number: big.NewInt(1), GasLimit: 30_000_000, GasUsed: 7_500_000, BaseFee: big.NewInt(1_000_000)}
+	if have := CalcBaseFeeWithParams(parent, 4, 8, 0); have.Cmp(parent.BaseFee) != 0 {
+		t.Errorf("custom target: have %s, want %s", have, parent.BaseFee)
+	}
+	parent.GasUsed = 0
+	if have, want := CalcBaseFeeWithParams(parent, 4, 8, 0), big.NewInt(875_000); have.Cmp(want) != 0 {
+		t.Errorf("unclamped decrease: have %s, want %s", have, want)
+	}
+}
+
+// newBaseFeeChain builds n linked headers two seconds apart starting at time
+// start, with correct base fees and alternating full and empty blocks.
+func newBaseFeeChain(config *params.ChainConfig, start uint64, n int) []*types.Header {
+	headers := []*types.Header{{
+		Number:   big.NewInt(1),
+		Time:     start,
+		GasLimit: 30_000_000,
+		BaseFee:  big.NewInt(1_000_000_000),
+	}}
+	for i := 1; i < n; i++ {
+		parent := headers[i-1]
+		header := &types.Header{
+			ParentHash: parent.Hash(),
+			Number:     new(big.Int).Add(parent.Number, common.Big1),
+			Time:       parent.Time + 2,
+			GasLimit:   parent.GasLimit,
+		}
+		if i%2 == 0 {
+			header.GasUsed = header.GasLimit
+		}
+		header.BaseFee = CalcBaseFee(config, parent, header.Time)
+		headers = append(headers, header)
+	}
+	return headers
+}
+
+func TestVerifyBaseFeeChain(t *testing.T) {
+	// The segment crosses the Bluebird activation at its fifth header
+	config := bluebirdConfig(1008)
+	headers := newBaseFeeChain(config, 1000, 10)
+	if err := VerifyBaseFeeChain(config, headers); err != nil {
+		t.Fatalf("valid segment rejected: %v", err)
+	}
+	if err := VerifyBaseFeeChain(config, headers[:1]); err != nil {
+		t.Fatalf("single header rejected: %v", err)
+	}
+	// A segment computed under pre-Bluebird rules is rejected after activation
+	if err := VerifyBaseFeeChain(bluebirdConfig(2000), headers); err == nil {
+		t.Fatal("segment accepted with the wrong fork schedule")
+	}
+
+	// Tampering with one base fee is reported at its index. The child is relinked
+	// so that only the base fee check can fail.
+	tampered := make([]*types.Header, len(headers))
+	for i, header := range headers {
+		tampered[i] = types.CopyHeader(header)
+	}
+	tampered[6].BaseFee.Add(tampered[6].BaseFee, common.Big1)
+	for i := 7; i < len(tampered); i++ {
+		tampered[i].ParentHash = tampered[i-1].Hash()
+	}
+	err := VerifyBaseFeeChain(config, tampered)
+	if err == nil || !strings.Contains(err.Error(), "header 6") {
+		t.Fatalf("tampered base fee not reported at its index: %v", err)
+	}
+	// Headers that do not link up are rejected
+	if err := VerifyBaseFeeChain(config, []*types.Header{headers[0], headers[2]}); err == nil {
+		t.Fatal("non-contiguous headers accepted")
+	}
+}
+
+func TestGasTargetDelta(t *testing.T) {
+	config := bluebirdConfig(1000)
+	for _, tc := range []struct {
+		time    uint64
+		gasUsed uint64
+		want    int64
+	}{
+		// Pre-Bluebird blocks target half of the 30M gas limit
+		{999, 15_000_000, 0},
+		{999, 20_000_000, 5_000_000},
+		{999, 10_000_000, -5_000_000},
+		// Bluebird blocks target a third of it
+		{1000, 10_000_000, 0},
+		{1000, 15_000_000, 5_000_000},
+		{1000, 0, -10_000_000},
+	} {
+		header := &types.Header{Number: big.NewInt(1), GasLimit: 30_000_000, GasUsed: tc.gasUsed}
+		if have := GasTargetDelta(config, header, tc.time); have != tc.want {
+			t.Errorf("time %d, gas used %d: delta mismatch: have %d, want %d", tc.time, tc.gasUsed, have, tc.want)
+		}
+	}
+	// Out of range deltas saturate instead of wrapping
+	header := &types.Header{Number: big.NewInt(1), GasLimit: math.MaxUint64, GasUsed: math.MaxUint64}
+	if have := GasTargetDelta(config, header, 1000); have != math.MaxInt64 {
+		t.Errorf("saturated delta mismatch: have %d, want %d", have, int64(math.MaxInt64))
+	}
+}
+
+// TestCalcBaseFeeGasLimitChange checks that the Bluebird gas target is derived
+// from the parent's gas limit when the child changes it.
+func TestCalcBaseFeeGasLimitChange(t *testing.T) {
+	config := bluebirdConfig(0)
+	parent := &types.Header{
+		Number:   big.NewInt(1),
+		Time:     1,
+		GasLimit: 30_000_000,
+		GasUsed:  10_000_000, // exactly the parent's Bluebird target
+		BaseFee:  big.NewInt(1_000_000_000),
+	}
+	child := &types.Header{
+		Number:   big.NewInt(2),
+		Time:     3,
+		GasLimit: parent.GasLimit + parent.GasLimit/1024 - 1, // largest allowed increase
+	}
+	// Measured against the parent's target the parent block is exactly at target,
+	// so the base fee is unchanged
+	child.BaseFee = CalcBaseFee(config, parent, child.Time)
+	if child.BaseFee.Cmp(parent.BaseFee) != 0 {
+		t.Fatalf("base fee mismatch: have %s, want %s", child.BaseFee, parent.BaseFee)
+	}
+	if err := VerifyEIP1559Header(config, parent, child); err != nil {
+		t.Fatalf("header rejected: %v", err)
+	}
+	// Deriving the target from the child's limit would have lowered the base fee,
+	// and such a header must be rejected
+	withChildLimit := types.CopyHeader(parent)
+	withChildLimit.GasLimit = child.GasLimit
+	child.BaseFee = CalcBaseFee(config, withChildLimit, child.Time)
+	if child.BaseFee.Cmp(parent.BaseFee) >= 0 {
+		t.Fatalf("child target does not lower the base fee: %s", child.BaseFee)
+	}
+	if err := VerifyEIP1559Header(config, parent, child); err == nil {
+		t.Fatal("header with base fee from child's gas limit accepted")
+	}
+}
+
+func BenchmarkCalcBaseFee(b *testing.B) {
+	config := bluebirdConfig(0)
+	parent := &types.Header{
+		Number:   big.NewInt(1),
+		GasLimit: 30_000_000,
+		BaseFee:  big.NewInt(1_000_000_000),
+	}
+	for _, bc := range []struct {
+		name    string
+		gasUsed uint64
+	}{
+		{"at-target", 30_000_000 / params.BluebirdElasticityMultiplier},
+		{"above-target", 30_000_000},
+		{"below-target", 1_000_000},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			parent := types.CopyHeader(parent)
+			parent.GasUsed = bc.gasUsed
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				CalcBaseFee(config, parent, 1)
+			}
+		})
+	}
+}
@@ -22,12 +22,20 @@ import (
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/consensus/misc"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
 )
 
+// baseFeeClampedCounter counts the verified headers whose base fee was clamped to
+// the Bluebird minimum. Only header verification is counted, so that projections,
+// what-if analysis and fee suggestions do not inflate it.
+var baseFeeClampedCounter = metrics.NewRegisteredCounter("eip1559/basefee/clamped", nil)
+
 // VerifyEIP1559Header verifies some header attributes which were changed in EIP-1559,
 // - gas limit check
 // - basefee check
@@ -47,28 +55,165 @@ func VerifyEIP1559Header(config *params.ChainConfig, parent, header *types.Heade
 		return errors.New("header is missing baseFee")
 	}
 	// Verify the baseFee is correct based on the parent header.
-	expectedBaseFee := CalcBaseFee(config, parent, header.Time)
+	expectedBaseFee, _, _, clamped := calcBaseFee(config, parent, header.Time)
 	if header.BaseFee.Cmp(expectedBaseFee) != 0 {
 		return fmt.Errorf("invalid baseFee: have %s, want %s, parentBaseFee %s, parentGasUsed %d",
 			header.BaseFee, expectedBaseFee, parent.BaseFee, parent.GasUsed)
 	}
+	if clamped {
+		baseFeeClampedCounter.Inc(1)
+	}
+	return nil
+}
+
+// VerifyBaseFeeChain verifies the base fee of every header in a segment of
+// consecutive headers against the one calculated from its predecessor, using the
+// fork parameters selected by each header's time. The first header is taken as
+// given. The error of the first mismatch reports its index and both values.
+func VerifyBaseFeeChain(config *params.ChainConfig, headers []*types.Header) error {
+	for i := 1; i < len(headers); i++ {
+		parent, header := headers[i-1], headers[i]
+		if header.ParentHash != parent.Hash() {
+			return fmt.Errorf("non-contiguous headers at index %d: parent hash %v, want %v", i, header.ParentHash, parent.Hash())
+		}
+		if header.BaseFee == nil {
+			return fmt.Errorf("header %d (number %v) is missing baseFee", i, header.Number)
+		}
+		if expected := CalcBaseFee(config, parent, header.Time); header.BaseFee.Cmp(expected) != 0 {
+			return fmt.Errorf("invalid baseFee of header %d (number %v): have %s, want %s, parentBaseFee %s, parentGasUsed %d",
+				i, header.Number, header.BaseFee, expected, parent.BaseFee, parent.GasUsed)
+		}
+	}
 	return nil
 }
 
 // CalcBaseFee calculates the basefee of the header.
-// The time belongs to the new block to check if Canyon is activted or not
+// The time belongs to the new block and selects the fork parameters, so the first
+// block at or after a fork (e.g. Canyon or Bluebird) already uses the new rules,
+// even though its parent was built under the old ones.
 func CalcBaseFee(config *params.ChainConfig, parent *types.Header, time uint64) *big.Int {
+	baseFee, _, _ := CalcBaseFeeDebug(config, parent, time)
+	return baseFee
+}
+
+// CalcBaseFeeDebug calculates the basefee of the header like CalcBaseFee, and
+// additionally returns the intermediate values of the calculation for diagnosing
+// base fee disputes. Both deltas are signed: gasDelta is the parent gas used minus
+// the parent gas target, and baseFeeDelta is the change applied to the parent base
+// fee (including any Bluebird minimum clamping), so that parent.BaseFee plus
+// baseFeeDelta always equals the returned base fee. Before London both are zero.
+func CalcBaseFeeDebug(config *params.ChainConfig, parent *types.Header, time uint64) (baseFee, gasDelta, baseFeeDelta *big.Int) {
+	baseFee, gasDelta, baseFeeDelta, _ = calcBaseFee(config, parent, time)
+	return baseFee, gasDelta, baseFeeDelta
+}
+
+// CalcBaseFeeUint64 is like CalcBaseFee, but returns the base fee as a uint64. The
+// boolean is false, and the value zero, if the base fee does not fit in a uint64.
+func CalcBaseFeeUint64(config *params.ChainConfig, parent *types.Header, time uint64) (uint64, bool) {
+	baseFee := CalcBaseFee(config, parent, time)
+	if !baseFee.IsUint64() {
+		return 0, false
+	}
+	return baseFee.Uint64(), true
+}
+
+// baseFeeCacheLimit is the number of base fees retained by CalcBaseFeeCached.
+const baseFeeCacheLimit = 256
+
+// baseFeeCacheKey identifies a cached base fee by the parent hash and the fee
+// parameters in effect for the child, rather than the chain config, so that equal
+// configs share entries and chains with different parameters never collide.
+type baseFeeCacheKey struct {
+	parent      common.Hash
+	elasticity  uint64
+	denominator uint64
+	minBaseFee  uint64
+}
+
+var baseFeeCache = lru.NewCache[baseFeeCacheKey, *big.Int](baseFeeCacheLimit)
+
+// CalcBaseFeeCached is like CalcBaseFee, but memoizes the result for block building,
+// which repeatedly computes the base fee of the same child. The parent hash must
+// be the hash of parent; it is passed in as callers already know it, and hashing
+// the header would cost more than the calculation. The returned value is a copy
+// and may be modified.
+func CalcBaseFeeCached(config *params.ChainConfig, parent *types.Header, parentHash common.Hash, time uint64) *big.Int {
+	// The initial base fee does not depend on the parent, don't bother caching it
+	if !config.IsLondon(parent.Number) {
+		return CalcBaseFee(config, parent, time)
+	}
+	elasticity, denominator, minBaseFee := baseFeeParams(config, time)
+	key := baseFeeCacheKey{parentHash, elasticity, denominator, minBaseFee}
+	if baseFee, ok := baseFeeCache.Get(key); ok {
+		return new(big.Int).Set(baseFee)
+	}
+	baseFee, _, _, _ := calcBaseFeeWithParams(parent, elasticity, denominator, minBaseFee)
+	baseFeeCache.Add(key, new(big.Int).Set(baseFee))
+	return baseFee
+}
+
+// ClearBaseFeeCache drops all base fees memoized by CalcBaseFeeCached.
+func ClearBaseFeeCache() {
+	baseFeeCache.Purge()
+}
+
+// CalcBaseFeeWithFlag calculates the basefee of the header like CalcBaseFee, and
+// additionally reports whether the result was clamped to the Bluebird minimum.
+func CalcBaseFeeWithFlag(config *params.ChainConfig, parent *types.Header, time uint64) (*big.Int, bool) {
+	baseFee, _, _, clamped := calcBaseFee(config, parent, time)
+	return baseFee, clamped
+}
+
+// CalcBaseFeeWithParams calculates the basefee of the child of parent under the
+// given EIP-1559 parameters rather than those of a chain config, for what-if
+// analysis. A non-zero minBaseFee acts as a floor like the Bluebird minimum does.
+// The elasticity and denominator must be non-zero, and parent must be a London
+// block, as the initial base fee of the first London block is not handled.
+func CalcBaseFeeWithParams(parent *types.Header, elasticity, denominator, minBaseFee uint64) *big.Int {
+	baseFee, _, _, _ := calcBaseFeeWithParams(parent, elasticity, denominator, minBaseFee)
+	return baseFee
+}
+
+// calcBaseFee implements the basefee calculation shared by CalcBaseFee and its
+// variants, returning the signed gas and base fee deltas along with the result.
+func calcBaseFee(config *params.ChainConfig, parent *types.Header, time uint64) (baseFee, gasDelta, baseFeeDelta *big.Int, clamped bool) {
 	// If the current block is the first EIP-1559 block, return the InitialBaseFee.
 	if !config.IsLondon(parent.Number) {
-		return new(big.Int).SetUint64(params.InitialBaseFee)
+		return new(big.Int).SetUint64(params.InitialBaseFee), new(big.Int), new(big.Int), false
 	}
+	elasticity, denominator, minBaseFee := baseFeeParams(config, time)
+	return calcBaseFeeWithParams(parent, elasticity, denominator, minBaseFee)
+}
+
+// baseFeeParams returns the EIP-1559 parameters of the chain that apply to the
+// base fee calculation of a block at the given time. A misconfigured zero
+// denominator falls back to the default with a warning instead of crashing the
+// node with a division by zero.
+func baseFeeParams(config *params.ChainConfig, time uint64) (elasticity, denominator, minBaseFee uint64) {
+	p := config.BaseFeeParamsAt(time)
+	if p.Denominator == 0 {
+		log.Warn("Zero base fee change denominator configured, using default", "time", time, "default", params.DefaultBaseFeeChangeDenominator)
+		p.Denominator = params.DefaultBaseFeeChangeDenominator
+	}
+	return p.Elasticity, p.Denominator, p.MinBaseFee
+}
 
-	parentGasTarget := parent.GasLimit / config.ElasticityMultiplier(time)
+// calcBaseFeeWithParams calculates the basefee of the child of a London parent
+// from explicit EIP-1559 parameters, clamping decreases to minBaseFee.
+func calcBaseFeeWithParams(parent *types.Header, elasticity, denominator, minBaseFee uint64) (baseFee, gasDelta, baseFeeDelta *big.Int, clamped bool) {
+	// Per the spec, the target is derived from the parent's gas limit, as that is
+	// the limit the parent's gas usage was subject to. A change of the gas limit in
+	// the child only shifts the target of the child's own successor.
+	parentGasTarget := parent.GasLimit / elasticity
 	// If the parent gasUsed is the same as the target, the baseFee remains unchanged.
+	// This is the common case, so skip the delta computation and return a copy.
 	if parent.GasUsed == parentGasTarget {
-		return new(big.Int).Set(parent.BaseFee)
+		return new(big.Int).Set(parent.BaseFee), new(big.Int), new(big.Int), false
 	}
 
+	// The gas delta is a guarded uint64 subtraction that cannot wrap, and every
+	// product involving the base fee is computed on big.Int, so headers with gas
+	// values near the uint64 limit cannot overflow.
 	var (
 		num   = new(big.Int)
 		denom = new(big.Int)
@@ -77,27 +222,105 @@ func CalcBaseFee(config *params.ChainConfig, parent *types.Header, time uint64)
 	if parent.GasUsed > parentGasTarget {
 		// If the parent block used more gas than its target, the baseFee should increase.
 		// max(1, parentBaseFee * gasUsedDelta / parentGasTarget / baseFeeChangeDenominator)
-		num.SetUint64(parent.GasUsed - parentGasTarget)
-		num.Mul(num, parent.BaseFee)
+		//
+		// Both divisions truncate (floor), matching the reference EIP-1559 spec, and
+		// the max guarantees at least a 1 wei increase for any block above target,
+		// under Bluebird parameters too.
+		gasDelta = new(big.Int).SetUint64(parent.GasUsed - parentGasTarget)
+		num.Mul(gasDelta, parent.BaseFee)
 		num.Div(num, denom.SetUint64(parentGasTarget))
-		num.Div(num, denom.SetUint64(config.BaseFeeChangeDenominator(time)))
-		baseFeeDelta := math.BigMax(num, common.Big1)
+		num.Div(num, denom.SetUint64(denominator))
+		baseFeeDelta = new(big.Int).Set(math.BigMax(num, common.Big1))
 
-		return num.Add(parent.BaseFee, baseFeeDelta)
+		return num.Add(parent.BaseFee, baseFeeDelta), gasDelta, baseFeeDelta, false
 	} else {
 		// Otherwise if the parent block used less gas than its target, the baseFee should decrease.
 		// max(0, parentBaseFee * gasUsedDelta / parentGasTarget / baseFeeChangeDenominator)
-		num.SetUint64(parentGasTarget - parent.GasUsed)
-		num.Mul(num, parent.BaseFee)
+		gasDelta = new(big.Int).SetUint64(parentGasTarget - parent.GasUsed)
+		num.Mul(gasDelta, parent.BaseFee)
 		num.Div(num, denom.SetUint64(parentGasTarget))
-		num.Div(num, denom.SetUint64(config.BaseFeeChangeDenominator(time)))
-		baseFee := num.Sub(parent.BaseFee, num)
+		num.Div(num, denom.SetUint64(denominator))
+		baseFee = new(big.Int).Sub(parent.BaseFee, num)
+		gasDelta.Neg(gasDelta)
 
-		// Enforce minimum base fee for Bluebird
-		minBaseFee := common.Big0
-		if config.IsBluebird(time) {
-			minBaseFee = new(big.Int).SetUint64(params.BluebirdMinBaseFee)
+		// Enforce the minimum base fee (Bluebird)
+		if floor := new(big.Int).SetUint64(minBaseFee); baseFee.Cmp(floor) < 0 {
+			baseFee, clamped = floor, true
 		}
-		return math.BigMax(baseFee, minBaseFee)
+		baseFee = math.BigMax(baseFee, common.Big0)
+		return baseFee, gasDelta, new(big.Int).Sub(baseFee, parent.BaseFee), clamped
 	}
 }
+
+// projectedBlockInterval is the timestamp step between synthetic headers built by
+// ProjectBaseFee. It matches the L2 block time of OP-stack chains.
+const projectedBlockInterval = 2
+
+// ProjectBaseFee forecasts the base fee of the next blocks after parent, assuming
+// each of them uses gasUsedPerBlock gas. The first projected block has timestamp
+// startTime and every following one is projectedBlockInterval seconds later, so
+// fork-dependent parameters (e.g. Bluebird) switch at the right step.
+func ProjectBaseFee(config *params.ChainConfig, parent *types.Header, startTime uint64, blocks int, gasUsedPerBlock uint64) []*big.Int {
+	if blocks <= 0 {
+		return nil
+	}
+	var (
+		fees = make([]*big.Int, 0, blocks)
+		head = types.CopyHeader(parent)
+		ts   = startTime
+	)
+	for i := 0; i < blocks; i++ {
+		baseFee := CalcBaseFee(config, head, ts)
+		fees = append(fees, baseFee)
+
+		// Advance the synthetic header for the next step
+		head = &types.Header{
+			Number:   new(big.Int).Add(head.Number, common.Big1),
+			Time:     ts,
+			GasLimit: head.GasLimit,
+			GasUsed:  gasUsedPerBlock,
+			BaseFee:  new(big.Int).Set(baseFee),
+		}
+		ts += projectedBlockInterval
+	}
+	return fees
+}
+
+// BaseFeeDeltaPercent returns the signed integer percentage by which the base fee
+// of a block at the given time changes relative to its parent, truncated towards
+// zero. It returns nil if the parent has no base fee to compare against.
+func BaseFeeDeltaPercent(config *params.ChainConfig, parent *types.Header, time uint64) *big.Int {
+	if parent.BaseFee == nil || parent.BaseFee.Sign() == 0 {
+		return nil
+	}
+	delta := new(big.Int).Sub(CalcBaseFee(config, parent, time), parent.BaseFee)
+	delta.Mul(delta, big.NewInt(100))
+	return delta.Quo(delta, parent.BaseFee)
+}
+
+// BaseFeeParamsForBlock returns the EIP-1559 elasticity multiplier and base fee
+// change denominator in effect for the given block, and whether they are the
+// Bluebird parameters. It allows per-block annotation of fee history ranges.
+func BaseFeeParamsForBlock(config *params.ChainConfig, header *types.Header) (elasticity, denominator uint64, bluebird bool) {
+	return config.ElasticityMultiplier(header.Time), config.BaseFeeChangeDenominator(header.Time), config.IsBluebird(header.Time)
+}
+
+// GasTargetDelta returns how much gas header used above (positive) or below
+// (negative) its gas target, with the target derived from the elasticity in effect
+// at the given time, so Bluebird blocks are measured against the Bluebird target.
+// Deltas beyond the int64 range saturate.
+func GasTargetDelta(config *params.ChainConfig, header *types.Header, time uint64) int64 {
+	const maxDelta = 1<<63 - 1
+
+	target := header.GasLimit / config.ElasticityMultiplier(time)
+	if header.GasUsed >= target {
+		return int64(min(header.GasUsed-target, maxDelta))
+	}
+	return -int64(min(target-header.GasUsed, maxDelta))
+}
+
+// IsBluebirdTransitionBlock reports whether header is the first block at or
+// after the Bluebird activation time, i.e. its parent was still pre-Bluebird.
+func IsBluebirdTransitionBlock(config *params.ChainConfig, parent, header *types.Header) bool {
+	return !config.IsBluebird(parent.Time) && config.IsBluebird(header.Time)
+}
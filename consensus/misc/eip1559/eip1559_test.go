@@ -17,6 +17,7 @@
 package eip1559
 
 import (
+	"math"
 	"math/big"
 	"testing"
 
@@ -176,3 +177,145 @@ func TestCalcBaseFeeOptimism(t *testing.T) {
 		}
 	}
 }
+
+// TestCalcBaseFeeZeroDenominator checks that a misconfigured zero denominator
+// falls back to the default instead of dividing by zero.
+func TestCalcBaseFeeZeroDenominator(t *testing.T) {
+	var (
+		bluebird = uint64(0)
+		zero     = uint64(0)
+	)
+	// A zero Bluebird override must behave as if unset
+	overridden := opConfig()
+	overridden.BluebirdTime = &bluebird
+	overridden.BluebirdBaseFeeChangeDenominatorOverride = &zero
+	unset := opConfig()
+	unset.BluebirdTime = &bluebird
+
+	// A zero pre-Bluebird denominator falls back to the default
+	misconfigured := opConfig()
+	misconfigured.Optimism.EIP1559Denominator = 0
+	fallback := opConfig()
+	fallback.Optimism.EIP1559Denominator = params.DefaultBaseFeeChangeDenominator
+
+	for _, gasUsed := range []uint64{4_000_000, 10_000_000} {
+		parent := &types.Header{
+			Number:   common.Big32,
+			GasLimit: 30_000_000,
+			GasUsed:  gasUsed,
+			BaseFee:  big.NewInt(params.InitialBaseFee),
+			Time:     6,
+		}
+		if have, want := CalcBaseFee(overridden, parent, 8), CalcBaseFee(unset, parent, 8); have.Cmp(want) != 0 {
+			t.Errorf("zero override, gas used %d: base fee mismatch: have %d, want %d", gasUsed, have, want)
+		}
+		if have, want := CalcBaseFee(misconfigured, parent, 8), CalcBaseFee(fallback, parent, 8); have.Cmp(want) != 0 {
+			t.Errorf("zero denominator, gas used %d: base fee mismatch: have %d, want %d", gasUsed, have, want)
+		}
+	}
+}
+
+func TestCalcBaseFeeDebug(t *testing.T) {
+	tests := []struct {
+		parentGasUsed        uint64
+		expectedGasDelta     int64
+		expectedBaseFeeDelta int64
+	}{
+		{10000000, 0, 0},               // usage == target
+		{9000000, -1000000, -12500000}, // usage below target
+		{11000000, 1000000, 12500000},  // usage above target
+	}
+	for i, test := range tests {
+		parent := &types.Header{
+			Number:   common.Big32,
+			GasLimit: 20000000,
+			GasUsed:  test.parentGasUsed,
+			BaseFee:  big.NewInt(params.InitialBaseFee),
+		}
+		baseFee, gasDelta, baseFeeDelta := CalcBaseFeeDebug(config(), parent, 0)
+		if want := CalcBaseFee(config(), parent, 0); baseFee.Cmp(want) != 0 {
+			t.Errorf("test %d: base fee mismatch: have %d, want %d", i, baseFee, want)
+		}
+		if gasDelta.Cmp(big.NewInt(test.expectedGasDelta)) != 0 {
+			t.Errorf("test %d: gas delta mismatch: have %d, want %d", i, gasDelta, test.expectedGasDelta)
+		}
+		if baseFeeDelta.Cmp(big.NewInt(test.expectedBaseFeeDelta)) != 0 {
+			t.Errorf("test %d: base fee delta mismatch: have %d, want %d", i, baseFeeDelta, test.expectedBaseFeeDelta)
+		}
+		if sum := new(big.Int).Add(parent.BaseFee, baseFeeDelta); sum.Cmp(baseFee) != 0 {
+			t.Errorf("test %d: parent base fee plus delta mismatch: have %d, want %d", i, sum, baseFee)
+		}
+	}
+}
+
+func TestCalcBaseFeeCached(t *testing.T) {
+	ClearBaseFeeCache()
+	defer ClearBaseFeeCache()
+
+	config := config()
+	parent := &types.Header{
+		Number:   common.Big32,
+		GasLimit: 20000000,
+		GasUsed:  15000000,
+		BaseFee:  big.NewInt(params.InitialBaseFee),
+	}
+	hash := parent.Hash()
+	want := CalcBaseFee(config, parent, 0)
+	first := CalcBaseFeeCached(config, parent, hash, 0)
+	if first.Cmp(want) != 0 {
+		t.Fatalf("base fee mismatch: have %s, want %s", first, want)
+	}
+	// Cache hits must be equal but never alias the cached value
+	second := CalcBaseFeeCached(config, parent, hash, 0)
+	if second.Cmp(want) != 0 || second == first {
+		t.Fatalf("cache hit mismatch: have %s (%p), first %s (%p)", second, second, first, first)
+	}
+	second.SetUint64(1)
+	if have := CalcBaseFeeCached(config, parent, hash, 0); have.Cmp(want) != 0 {
+		t.Fatalf("cached value mutated by caller: have %s, want %s", have, want)
+	}
+	// Equal configs share the entry
+	equal := *config
+	if have := CalcBaseFeeCached(&equal, parent, hash, 0); have.Cmp(want) != 0 {
+		t.Fatalf("base fee mismatch for equal config: have %s, want %s", have, want)
+	}
+	if baseFeeCache.Len() != 1 {
+		t.Fatalf("cache size mismatch: have %d, want 1", baseFeeCache.Len())
+	}
+	// Different fee parameters do not collide
+	bluebird := uint64(0)
+	other := *config
+	other.BluebirdTime = &bluebird
+	if have, want := CalcBaseFeeCached(&other, parent, hash, 0), CalcBaseFee(&other, parent, 0); have.Cmp(want) != 0 {
+		t.Fatalf("base fee mismatch for other config: have %s, want %s", have, want)
+	}
+	if baseFeeCache.Len() != 2 {
+		t.Fatalf("cache size mismatch: have %d, want 2", baseFeeCache.Len())
+	}
+	ClearBaseFeeCache()
+	if baseFeeCache.Len() != 0 {
+		t.Fatalf("cache not cleared: %d entries left", baseFeeCache.Len())
+	}
+	if have := CalcBaseFeeCached(config, parent, hash, 0); have.Cmp(want) != 0 {
+		t.Fatalf("base fee mismatch after clearing: have %s, want %s", have, want)
+	}
+}
+
+func TestCalcBaseFeeUint64(t *testing.T) {
+	config := config()
+	parent := &types.Header{
+		Number:   common.Big32,
+		GasLimit: 20000000,
+		GasUsed:  20000000,
+		BaseFee:  big.NewInt(params.InitialBaseFee),
+	}
+	want := CalcBaseFee(config, parent, 0)
+	if have, ok := CalcBaseFeeUint64(config, parent, 0); !ok || have != want.Uint64() {
+		t.Errorf("in-range base fee mismatch: have %d (ok %v), want %s", have, ok, want)
+	}
+	// A full block on a parent at the uint64 limit pushes the base fee past it
+	parent.BaseFee = new(big.Int).SetUint64(math.MaxUint64)
+	if have, ok := CalcBaseFeeUint64(config, parent, 0); ok || have != 0 {
+		t.Errorf("out-of-range base fee accepted: have %d (ok %v)", have, ok)
+	}
+}
@@ -68,6 +68,9 @@ func (v *BlockValidator) ValidateBody(block *types.Block) error {
 	if hash := types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil)); hash != header.TxHash {
 		return fmt.Errorf("transaction root hash mismatch (header value %x, calculated %x)", header.TxHash, hash)
 	}
+	if err := ValidateDepositTxs(v.config, header, block.Transactions()); err != nil {
+		return err
+	}
 
 	// Withdrawals are present after the Shanghai fork.
 	if header.WithdrawalsHash != nil {
@@ -0,0 +1,147 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ValidateDepositTxs verifies the deposit transactions included in a block body
+// against the given header. Deposits are force-included from L1, so these checks
+// guard against derivation bugs rather than user misbehaviour. The rules are only
+// enforced from Bluebird onwards, so that blocks from before the fork, which were
+// accepted without them, keep validating.
+func ValidateDepositTxs(config *params.ChainConfig, header *types.Header, txs types.Transactions) error {
+	if !config.IsBluebird(header.Time) {
+		return nil
+	}
+	seen := make(map[common.Hash]int)
+	for i, tx := range txs {
+		if !tx.IsDepositTx() {
+			continue
+		}
+		// Source hashes identify deposits across all versions, and a repeat means
+		// the same L1 event was derived twice.
+		if prev, ok := seen[tx.SourceHash()]; ok {
+			return fmt.Errorf("invalid deposit transaction %d: %w: source hash %v, first seen at %d",
+				i, ErrDepositDuplicateSourceHash, tx.SourceHash(), prev)
+		}
+		seen[tx.SourceHash()] = i
+
+		if tx.Type() != types.DepositTxV2Type {
+			continue
+		}
+		if err := validateDepositTxV2(config, header, tx); err != nil {
+			return fmt.Errorf("invalid deposit transaction %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// ValidateDepositNonceOrder verifies that the effective nonces of the nonce-wrapped
+// Bluebird deposits in txs are strictly increasing, as nonces are assigned in block
+// order. Deposits without an effective nonce are skipped. The check is optional
+// and not part of block validation, since the nonces are not consensus data.
+func ValidateDepositNonceOrder(txs types.Transactions) error {
+	var (
+		prev    uint64
+		prevIdx = -1
+	)
+	for i, tx := range txs {
+		if tx.Type() != types.DepositTxV2Type {
+			continue
+		}
+		nonce := tx.EffectiveNonce()
+		if nonce == nil {
+			continue
+		}
+		if prevIdx >= 0 && *nonce <= prev {
+			return fmt.Errorf("%w: deposit %d has nonce %d, deposit %d has nonce %d",
+				ErrDepositNonceOrder, i, *nonce, prevIdx, prev)
+		}
+		prev, prevIdx = *nonce, i
+	}
+	return nil
+}
+
+// ValidateDepositNonceUniqueness verifies that no two nonce-wrapped Bluebird
+// deposits in txs share both sender and effective nonce, which would leave the
+// sender's resulting nonce ambiguous. Deposits without an effective nonce are
+// skipped. Like ValidateDepositNonceOrder, the check is optional and not part of
+// block validation.
+func ValidateDepositNonceUniqueness(txs types.Transactions) error {
+	type senderNonce struct {
+		from  common.Address
+		nonce uint64
+	}
+	seen := make(map[senderNonce]int)
+	for i, tx := range txs {
+		if tx.Type() != types.DepositTxV2Type {
+			continue
+		}
+		nonce := tx.EffectiveNonce()
+		if nonce == nil {
+			continue
+		}
+		_, from, _, _, _, _, _ := tx.DepositInner()
+		key := senderNonce{from, *nonce}
+		if prev, ok := seen[key]; ok {
+			return fmt.Errorf("%w: deposit %d from %v has nonce %d, first seen at %d",
+				ErrDepositDuplicateNonce, i, from, *nonce, prev)
+		}
+		seen[key] = i
+	}
+	return nil
+}
+
+// validateDepositTxV2 verifies a single Bluebird deposit, bare or nonce-wrapped.
+func validateDepositTxV2(config *params.ChainConfig, header *types.Header, tx *types.Transaction) error {
+	if tx.SourceHash() == (common.Hash{}) {
+		return fmt.Errorf("%w: tx hash %v", ErrDepositZeroSourceHash, tx.Hash())
+	}
+	// User deposits need gas to execute at all. System deposits are exempt: they
+	// are not metered against the block gas limit and may legitimately carry zero.
+	if !tx.IsSystemTx() && tx.Gas() == 0 {
+		return fmt.Errorf("%w: source hash %v", ErrDepositZeroGas, tx.SourceHash())
+	}
+	if tx.Gas() > header.GasLimit {
+		return fmt.Errorf("%w: source hash %v, gas %d, block gas limit %d",
+			ErrDepositGasLimitExceeded, tx.SourceHash(), tx.Gas(), header.GasLimit)
+	}
+	if tx.IsSystemTx() && !config.SystemTxAllowValue && tx.Value().Sign() != 0 {
+		return fmt.Errorf("%w: source hash %v, value %v", ErrSystemDepositValue, tx.SourceHash(), tx.Value())
+	}
+	// A system deposit without mint, value or data does nothing but burn gas, and
+	// is most likely a malformed L1 info deposit.
+	if config.StrictSystemDeposits && tx.IsSystemTx() && len(tx.Data()) == 0 &&
+		(tx.Mint() == nil || tx.Mint().Sign() == 0) && tx.Value().Sign() == 0 {
+		return fmt.Errorf("%w: source hash %v", ErrEmptySystemDeposit, tx.SourceHash())
+	}
+	if config.MaxDepositMint != nil && tx.Mint() != nil && tx.Mint().Cmp(config.MaxDepositMint) > 0 {
+		return fmt.Errorf("%w: source hash %v, mint %v, maximum %v",
+			ErrDepositMintExceeded, tx.SourceHash(), tx.Mint(), config.MaxDepositMint)
+	}
+	if config.MaxDepositDataSize != nil && uint64(len(tx.Data())) > *config.MaxDepositDataSize {
+		return fmt.Errorf("%w: source hash %v, size %d, maximum %d",
+			ErrDepositDataTooLarge, tx.SourceHash(), len(tx.Data()), *config.MaxDepositDataSize)
+	}
+	return nil
+}
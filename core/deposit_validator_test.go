@@ -0,0 +1,357 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/internal/deposittest"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// testBluebirdConfig returns a copy of the test chain config with Bluebird active
+// from genesis, as the deposit rules are only enforced from Bluebird onwards.
+func testBluebirdConfig() *params.ChainConfig {
+	var (
+		config   = *params.TestChainConfig
+		bluebird = uint64(0)
+	)
+	config.BluebirdTime = &bluebird
+	return &config
+}
+
+// testDepositVariants returns the fixture deposit built from opts, both bare and
+// wrapped with an effective nonce.
+func testDepositVariants(opts ...deposittest.Option) types.Transactions {
+	opts = opts[:len(opts):len(opts)]
+	return types.Transactions{deposittest.NewTx(opts...), deposittest.NewTx(append(opts, deposittest.WithNonce(42))...)}
+}
+
+func TestValidateDepositTxsGasLimit(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(1), GasLimit: 30_000_000}
+
+	for _, tc := range []struct {
+		gas     uint64
+		wantErr error
+	}{
+		{gas: header.GasLimit, wantErr: nil},
+		{gas: header.GasLimit + 1, wantErr: ErrDepositGasLimitExceeded},
+	} {
+		for i, tx := range testDepositVariants(deposittest.WithGas(tc.gas)) {
+			err := ValidateDepositTxs(testBluebirdConfig(), header, types.Transactions{tx})
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("gas %d, variant %d: error mismatch: have %v, want %v", tc.gas, i, err, tc.wantErr)
+			}
+		}
+	}
+}
+
+func TestValidateDepositTxsSystemValue(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(1), GasLimit: 30_000_000}
+
+	for _, tc := range []struct {
+		system     bool
+		value      int64
+		allowValue bool
+		wantErr    error
+	}{
+		{system: true, value: 0, allowValue: false, wantErr: nil},
+		{system: true, value: 1, allowValue: false, wantErr: ErrSystemDepositValue},
+		{system: true, value: 1, allowValue: true, wantErr: nil},
+		{system: false, value: 1, allowValue: false, wantErr: nil},
+	} {
+		config := *testBluebirdConfig()
+		config.SystemTxAllowValue = tc.allowValue
+
+		opts := []deposittest.Option{deposittest.WithValue(big.NewInt(tc.value))}
+		if tc.system {
+			opts = append(opts, deposittest.WithSystem())
+		}
+		for i, tx := range testDepositVariants(opts...) {
+			err := ValidateDepositTxs(&config, header, types.Transactions{tx})
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("system %v, value %d, allow %v, variant %d: error mismatch: have %v, want %v",
+					tc.system, tc.value, tc.allowValue, i, err, tc.wantErr)
+			}
+		}
+	}
+}
+
+func TestValidateDepositTxsSourceHash(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(1), GasLimit: 30_000_000}
+
+	for _, tc := range []struct {
+		sourceHash common.Hash
+		wantErr    error
+	}{
+		{sourceHash: common.HexToHash("0x01"), wantErr: nil},
+		{sourceHash: common.Hash{}, wantErr: ErrDepositZeroSourceHash},
+	} {
+		for i, tx := range testDepositVariants(deposittest.WithSourceHash(tc.sourceHash)) {
+			err := ValidateDepositTxs(testBluebirdConfig(), header, types.Transactions{tx})
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("source hash %v, variant %d: error mismatch: have %v, want %v", tc.sourceHash, i, err, tc.wantErr)
+			}
+		}
+	}
+}
+
+func TestValidateDepositTxsZeroGas(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(1), GasLimit: 30_000_000}
+
+	for _, tc := range []struct {
+		system  bool
+		gas     uint64
+		wantErr error
+	}{
+		{system: false, gas: 0, wantErr: ErrDepositZeroGas},
+		{system: false, gas: 1, wantErr: nil},
+		{system: true, gas: 0, wantErr: nil},
+	} {
+		opts := []deposittest.Option{deposittest.WithGas(tc.gas)}
+		if tc.system {
+			opts = append(opts, deposittest.WithSystem(), deposittest.WithValue(new(big.Int)))
+		}
+		for i, tx := range testDepositVariants(opts...) {
+			err := ValidateDepositTxs(testBluebirdConfig(), header, types.Transactions{tx})
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("system %v, gas %d, variant %d: error mismatch: have %v, want %v", tc.system, tc.gas, i, err, tc.wantErr)
+			}
+		}
+	}
+}
+
+func TestValidateDepositTxsDuplicateSourceHash(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(1), GasLimit: 30_000_000}
+	withSource := func(hash string) deposittest.Option {
+		return deposittest.WithSourceHash(common.HexToHash(hash))
+	}
+	legacy := types.NewTx(&deposittest.NewDepositTxV2(withSource("0x01")).DepositTx)
+	for _, tc := range []struct {
+		name    string
+		txs     types.Transactions
+		wantErr error
+	}{
+		{
+			name:    "unique",
+			txs:     types.Transactions{legacy, deposittest.NewTx(withSource("0x02")), deposittest.NewTx(withSource("0x03"), deposittest.WithNonce(42))},
+			wantErr: nil,
+		},
+		{
+			name:    "duplicate V2",
+			txs:     testDepositVariants(withSource("0x02")),
+			wantErr: ErrDepositDuplicateSourceHash,
+		},
+		{
+			name:    "duplicate across versions",
+			txs:     types.Transactions{legacy, deposittest.NewTx(withSource("0x01"))},
+			wantErr: ErrDepositDuplicateSourceHash,
+		},
+	} {
+		err := ValidateDepositTxs(testBluebirdConfig(), header, tc.txs)
+		if !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: error mismatch: have %v, want %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestValidateDepositTxsMaxMint(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(1), GasLimit: 30_000_000}
+
+	for _, tc := range []struct {
+		maxMint *big.Int
+		mint    *big.Int
+		wantErr error
+	}{
+		{maxMint: big.NewInt(1000), mint: big.NewInt(999), wantErr: nil},
+		{maxMint: big.NewInt(1000), mint: big.NewInt(1000), wantErr: nil},
+		{maxMint: big.NewInt(1000), mint: big.NewInt(1001), wantErr: ErrDepositMintExceeded},
+		{maxMint: big.NewInt(1000), mint: nil, wantErr: nil},
+		{maxMint: nil, mint: new(big.Int).Lsh(big.NewInt(1), 255), wantErr: nil},
+	} {
+		config := *testBluebirdConfig()
+		config.MaxDepositMint = tc.maxMint
+
+		for i, tx := range testDepositVariants(deposittest.WithMint(tc.mint)) {
+			err := ValidateDepositTxs(&config, header, types.Transactions{tx})
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("max %v, mint %v, variant %d: error mismatch: have %v, want %v",
+					tc.maxMint, tc.mint, i, err, tc.wantErr)
+			}
+		}
+	}
+}
+
+func TestValidateDepositTxsMaxDataSize(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(1), GasLimit: 30_000_000}
+	limit := uint64(16)
+
+	for _, tc := range []struct {
+		maxSize *uint64
+		size    int
+		wantErr error
+	}{
+		{maxSize: &limit, size: 0, wantErr: nil},
+		{maxSize: &limit, size: 16, wantErr: nil},
+		{maxSize: &limit, size: 17, wantErr: ErrDepositDataTooLarge},
+		{maxSize: nil, size: 1 << 20, wantErr: nil},
+	} {
+		config := *testBluebirdConfig()
+		config.MaxDepositDataSize = tc.maxSize
+
+		for i, tx := range testDepositVariants(deposittest.WithData(make([]byte, tc.size))) {
+			err := ValidateDepositTxs(&config, header, types.Transactions{tx})
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("max %v, size %d, variant %d: error mismatch: have %v, want %v",
+					tc.maxSize, tc.size, i, err, tc.wantErr)
+			}
+		}
+	}
+}
+
+func TestValidateDepositTxsStrictSystemDeposits(t *testing.T) {
+	header := &types.Header{Number: big.NewInt(1), GasLimit: 30_000_000}
+
+	for _, tc := range []struct {
+		strict  bool
+		system  bool
+		mint    *big.Int
+		data    []byte
+		wantErr error
+	}{
+		{strict: false, system: true, mint: nil, data: nil, wantErr: nil},
+		{strict: true, system: true, mint: nil, data: nil, wantErr: ErrEmptySystemDeposit},
+		{strict: true, system: true, mint: big.NewInt(0), data: nil, wantErr: ErrEmptySystemDeposit},
+		{strict: true, system: true, mint: big.NewInt(1), data: nil, wantErr: nil},
+		{strict: true, system: true, mint: nil, data: []byte{0x01}, wantErr: nil},
+		{strict: true, system: false, mint: nil, data: nil, wantErr: nil},
+	} {
+		config := *testBluebirdConfig()
+		config.StrictSystemDeposits = tc.strict
+
+		opts := []deposittest.Option{deposittest.WithMint(tc.mint), deposittest.WithValue(new(big.Int)), deposittest.WithData(tc.data)}
+		if tc.system {
+			opts = append(opts, deposittest.WithSystem())
+		}
+		for i, tx := range testDepositVariants(opts...) {
+			err := ValidateDepositTxs(&config, header, types.Transactions{tx})
+			if !errors.Is(err, tc.wantErr) {
+				t.Errorf("strict %v, system %v, mint %v, data %x, variant %d: error mismatch: have %v, want %v",
+					tc.strict, tc.system, tc.mint, tc.data, i, err, tc.wantErr)
+			}
+		}
+	}
+}
+
+func TestValidateDepositNonceOrder(t *testing.T) {
+	wrap := func(nonce uint64) *types.Transaction { return deposittest.NewTx(deposittest.WithNonce(nonce)) }
+	legacy := types.NewTx(&types.LegacyTx{Nonce: 0, GasPrice: big.NewInt(1), Gas: 21000})
+
+	for _, tc := range []struct {
+		name    string
+		txs     types.Transactions
+		wantErr error
+	}{
+		{
+			name:    "ordered",
+			txs:     types.Transactions{wrap(1), deposittest.NewTx(), wrap(2), legacy, wrap(5)},
+			wantErr: nil,
+		},
+		{
+			name:    "out of order",
+			txs:     types.Transactions{wrap(2), legacy, wrap(1)},
+			wantErr: ErrDepositNonceOrder,
+		},
+		{
+			name:    "repeated",
+			txs:     types.Transactions{wrap(3), wrap(3)},
+			wantErr: ErrDepositNonceOrder,
+		},
+		{
+			name:    "empty",
+			txs:     nil,
+			wantErr: nil,
+		},
+	} {
+		if err := ValidateDepositNonceOrder(tc.txs); !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: error mismatch: have %v, want %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestValidateDepositNonceUniqueness(t *testing.T) {
+	var (
+		other = deposittest.WithFrom(common.HexToAddress("0xabcd"))
+		wrap  = func(nonce uint64, opts ...deposittest.Option) *types.Transaction {
+			return deposittest.NewTx(append(opts, deposittest.WithNonce(nonce))...)
+		}
+	)
+	for _, tc := range []struct {
+		name    string
+		txs     types.Transactions
+		wantErr error
+	}{
+		{
+			name:    "unique",
+			txs:     types.Transactions{wrap(1), wrap(1, other), deposittest.NewTx(), deposittest.NewTx(), wrap(2)},
+			wantErr: nil,
+		},
+		{
+			name:    "duplicate",
+			txs:     types.Transactions{wrap(3), wrap(4, other), wrap(3)},
+			wantErr: ErrDepositDuplicateNonce,
+		},
+		{
+			name:    "empty",
+			txs:     nil,
+			wantErr: nil,
+		},
+	} {
+		if err := ValidateDepositNonceUniqueness(tc.txs); !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: error mismatch: have %v, want %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
+
+func TestValidateDepositTxsPreBluebird(t *testing.T) {
+	var (
+		bluebird = uint64(100)
+		config   = *params.TestChainConfig
+		// A duplicate V1 deposit, a value-carrying system deposit and a zero gas
+		// user deposit, all rejected from Bluebird onwards
+		txs = types.Transactions{
+			types.NewTx(&deposittest.NewDepositTxV2(deposittest.WithSourceHash(common.HexToHash("0x01"))).DepositTx),
+			types.NewTx(&deposittest.NewDepositTxV2(deposittest.WithSourceHash(common.HexToHash("0x01"))).DepositTx),
+			deposittest.NewTx(deposittest.WithSystem(), deposittest.WithValue(big.NewInt(1))),
+			deposittest.NewTx(deposittest.WithSourceHash(common.HexToHash("0x02")), deposittest.WithGas(0)),
+		}
+	)
+	config.BluebirdTime = &bluebird
+
+	header := &types.Header{Number: big.NewInt(1), Time: bluebird - 1, GasLimit: 30_000_000}
+	if err := ValidateDepositTxs(&config, header, txs); err != nil {
+		t.Errorf("pre-Bluebird block rejected: %v", err)
+	}
+	header.Time = bluebird
+	if err := ValidateDepositTxs(&config, header, txs); !errors.Is(err, ErrDepositDuplicateSourceHash) {
+		t.Errorf("Bluebird block error mismatch: have %v, want %v", err, ErrDepositDuplicateSourceHash)
+	}
+}
@@ -119,3 +119,47 @@ var (
 	// ErrTxGasLimitTooHigh is returned if a transaction's gas limit is too high.
 	ErrTxGasLimitTooHigh = errors.New("transaction gas limit too high")
 )
+
+// List of errors raised when verifying the deposit transactions of a block body.
+var (
+	// ErrDepositGasLimitExceeded is returned if a deposit transaction's gas limit
+	// exceeds the gas limit of the block it is included in.
+	ErrDepositGasLimitExceeded = errors.New("deposit gas limit exceeds block gas limit")
+
+	// ErrSystemDepositValue is returned if a system deposit transfers value on a
+	// chain that does not allow it.
+	ErrSystemDepositValue = errors.New("system deposit transfers value")
+
+	// ErrDepositZeroSourceHash is returned if a deposit transaction has no source
+	// hash, which is what uniquely identifies it.
+	ErrDepositZeroSourceHash = errors.New("deposit has zero source hash")
+
+	// ErrDepositZeroGas is returned if a user deposit transaction has no gas, and
+	// could therefore never execute.
+	ErrDepositZeroGas = errors.New("user deposit has zero gas")
+
+	// ErrDepositDuplicateSourceHash is returned if two deposit transactions of the
+	// same block share a source hash.
+	ErrDepositDuplicateSourceHash = errors.New("duplicate deposit source hash")
+
+	// ErrDepositMintExceeded is returned if a deposit transaction mints more than
+	// the chain's configured per-deposit maximum.
+	ErrDepositMintExceeded = errors.New("deposit mint exceeds maximum")
+
+	// ErrDepositNonceOrder is returned if the effective nonces of the V2 deposits
+	// of a block are not strictly increasing.
+	ErrDepositNonceOrder = errors.New("deposit nonces out of order")
+
+	// ErrDepositDuplicateNonce is returned if two V2 deposits of a block carry the
+	// same sender and effective nonce.
+	ErrDepositDuplicateNonce = errors.New("duplicate deposit sender nonce")
+
+	// ErrDepositDataTooLarge is returned if a deposit transaction's calldata
+	// exceeds the chain's configured per-deposit maximum size.
+	ErrDepositDataTooLarge = errors.New("deposit data exceeds maximum size")
+
+	// ErrEmptySystemDeposit is returned on chains with strict system deposits if
+	// a system deposit carries no mint, value or data, which usually indicates a
+	// malformed L1 info deposit.
+	ErrEmptySystemDeposit = errors.New("empty system deposit")
+)
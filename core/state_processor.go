@@ -157,10 +157,15 @@ func ApplyTransactionWithEVM(msg *Message, config *params.ChainConfig, gp *GasPo
 		// otherwise must be nil.
 		receipt.DepositNonce = &nonce
 		// The DepositReceiptVersion for deposit transactions is only recorded from Canyon onwards
-		// and otherwise must be nil.
-		if config.IsOptimismCanyon(evm.Context.Time) {
-			receipt.DepositReceiptVersion = new(uint64)
-			*receipt.DepositReceiptVersion = types.CanyonDepositReceiptVersion
+		// and otherwise must be nil, unless derivation annotated the deposit with one.
+		receipt.DepositReceiptVersion = depositReceiptVersion(config, evm.Context.Time, tx)
+	}
+	if msg.IsDepositTx {
+		// Deposits are priced at the base fee of the including block, matching the
+		// value DeriveFields reports for stored receipts.
+		receipt.EffectiveGasPrice = new(big.Int)
+		if evm.Context.BaseFee != nil {
+			receipt.EffectiveGasPrice.Set(evm.Context.BaseFee)
 		}
 	}
 	if tx.Type() == types.BlobTxType {
@@ -182,6 +187,20 @@ func ApplyTransactionWithEVM(msg *Message, config *params.ChainConfig, gp *GasPo
 	return receipt, err
 }
 
+// depositReceiptVersion returns the receipt version of a deposit executed at the
+// given time. A version annotated by derivation takes precedence; otherwise the
+// version is only recorded from Canyon onwards.
+func depositReceiptVersion(config *params.ChainConfig, time uint64, tx *types.Transaction) *uint64 {
+	if version := tx.DepositReceiptVersion(); version != nil {
+		return version
+	}
+	if !config.IsOptimismCanyon(time) {
+		return nil
+	}
+	version := types.CanyonDepositReceiptVersion
+	return &version
+}
+
 // ApplyTransaction attempts to apply a transaction to the given state database
 // and uses the input parameters for its environment. It returns the receipt
 // for the transaction, gas used and an error if the transaction failed,
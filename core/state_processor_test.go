@@ -19,6 +19,7 @@ package core
 import (
 	"crypto/ecdsa"
 	"math/big"
+	"reflect"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -29,9 +30,11 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/internal/deposittest"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/holiman/uint256"
@@ -528,3 +531,279 @@ func TestProcessVerkle(t *testing.T) {
 		}
 	}
 }
+
+// TestDepositTxV2MintSpendable checks that the amount minted by a Bluebird deposit
+// is credited before execution, so that a later transaction in the same block can
+// spend it.
+func TestDepositTxV2MintSpendable(t *testing.T) {
+	var (
+		zero   = uint64(0)
+		config = *params.OptimismTestConfig
+		key, _ = crypto.GenerateKey()
+		sender = crypto.PubkeyToAddress(key.PublicKey)
+		dest   = common.Address{0xde, 0xad}
+		mint   = big.NewInt(params.Ether)
+		amount = big.NewInt(params.Ether / 2)
+	)
+	config.BedrockBlock = big.NewInt(0)
+	config.RegolithTime = &zero
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	header := &types.Header{
+		Number:     big.NewInt(1),
+		Time:       1,
+		GasLimit:   30_000_000,
+		BaseFee:    big.NewInt(1),
+		Difficulty: common.Big0,
+	}
+	signer := types.MakeSigner(&config, header.Number, header.Time)
+	deposit := types.NewTx(&types.DepositTxV2{DepositTx: types.DepositTx{
+		SourceHash: common.HexToHash("0xdeadbeef"),
+		From:       sender,
+		To:         &sender,
+		Mint:       mint,
+		Value:      common.Big0,
+		Gas:        100_000,
+	}})
+	transfer, err := types.SignTx(types.NewTransaction(1, dest, amount, params.TxGas, big.NewInt(1), nil), signer, key)
+	if err != nil {
+		t.Fatalf("failed to sign transfer: %v", err)
+	}
+	var (
+		gp      = new(GasPool).AddGas(header.GasLimit)
+		usedGas uint64
+	)
+	for i, tx := range []*types.Transaction{deposit, transfer} {
+		statedb.SetTxContext(tx.Hash(), i)
+		receipt, err := ApplyTransaction(&config, nil, &common.Address{}, gp, statedb, header, tx, &usedGas, vm.Config{})
+		if err != nil {
+			t.Fatalf("tx %d: failed to apply: %v", i, err)
+		}
+		if receipt.Status != types.ReceiptStatusSuccessful {
+			t.Fatalf("tx %d: execution failed", i)
+		}
+	}
+	if have := statedb.GetBalance(dest).ToBig(); have.Cmp(amount) != 0 {
+		t.Errorf("recipient balance mismatch: have %v, want %v", have, amount)
+	}
+	// Both transactions pay for their gas at the base fee, deposits included
+	want := new(big.Int).Sub(mint, amount)
+	want.Sub(want, new(big.Int).Mul(new(big.Int).SetUint64(usedGas), header.BaseFee))
+	if have := statedb.GetBalance(sender).ToBig(); have.Cmp(want) != 0 {
+		t.Errorf("sender balance mismatch: have %v, want %v", have, want)
+	}
+}
+
+// TestDepositTxV2OutOfGasReceipt checks that a nonce-wrapped Bluebird deposit that
+// runs out of gas is still included with a failed receipt, which records the
+// deposit nonce the deposit was executed with.
+func TestDepositTxV2OutOfGasReceipt(t *testing.T) {
+	var (
+		zero   = uint64(0)
+		nonce  = uint64(7)
+		config = *params.OptimismTestConfig
+		sender = common.Address{0x5e, 0xde}
+		loop   = common.Address{0x10, 0x09}
+	)
+	config.BedrockBlock = big.NewInt(0)
+	config.RegolithTime = &zero
+
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+	statedb.SetNonce(sender, nonce)
+	statedb.SetCode(loop, []byte{byte(vm.JUMPDEST), byte(vm.PUSH1), 0x00, byte(vm.JUMP)})
+
+	header := &types.Header{
+		Number:     big.NewInt(1),
+		Time:       1,
+		GasLimit:   30_000_000,
+		BaseFee:    big.NewInt(1),
+		Difficulty: common.Big0,
+	}
+	deposit := deposittest.NewTx(
+		deposittest.WithFrom(sender),
+		deposittest.WithTo(loop),
+		deposittest.WithMint(big.NewInt(params.Ether)),
+		deposittest.WithValue(common.Big0),
+		deposittest.WithGas(100_000),
+		deposittest.WithData(nil),
+		deposittest.WithNonce(nonce),
+	)
+
+	var (
+		gp      = new(GasPool).AddGas(header.GasLimit)
+		usedGas uint64
+	)
+	statedb.SetTxContext(deposit.Hash(), 0)
+	receipt, err := ApplyTransaction(&config, nil, &common.Address{}, gp, statedb, header, deposit, &usedGas, vm.Config{})
+	if err != nil {
+		t.Fatalf("failed to apply deposit: %v", err)
+	}
+	if receipt.Status != types.ReceiptStatusFailed {
+		t.Errorf("receipt status mismatch: have %d, want %d", receipt.Status, types.ReceiptStatusFailed)
+	}
+	if receipt.GasUsed != deposit.Gas() {
+		t.Errorf("gas used mismatch: have %d, want %d", receipt.GasUsed, deposit.Gas())
+	}
+	if receipt.DepositNonce == nil || *receipt.DepositNonce != *deposit.EffectiveNonce() {
+		t.Errorf("deposit nonce mismatch: have %v, want %d", receipt.DepositNonce, *deposit.EffectiveNonce())
+	}
+	// The failed deposit still consumes the nonce
+	if have := statedb.GetNonce(sender); have != nonce+1 {
+		t.Errorf("sender nonce mismatch: have %d, want %d", have, nonce+1)
+	}
+}
+
+func TestDepositIntrinsicGas(t *testing.T) {
+	var (
+		zero     = uint64(0)
+		bluebird = uint64(1000)
+		config   = *params.OptimismTestConfig
+		data     = []byte("test data")
+	)
+	config.BluebirdTime = &bluebird
+
+	full, err := IntrinsicGas(data, nil, false, true, true, true)
+	if err != nil {
+		t.Fatalf("failed to compute intrinsic gas: %v", err)
+	}
+	for _, tc := range []struct {
+		name string
+		time uint64
+		msg  *Message
+		want uint64
+	}{
+		{"v2 deposit below base", bluebird, &Message{IsDepositTx: true, IsDepositTxV2: true, GasLimit: 10_000}, 10_000},
+		{"v2 deposit above base", bluebird, &Message{IsDepositTx: true, IsDepositTxV2: true, GasLimit: 50_000}, full},
+		{"v2 deposit pre-bluebird", zero, &Message{IsDepositTx: true, IsDepositTxV2: true, GasLimit: 10_000}, full},
+		{"v1 deposit", bluebird, &Message{IsDepositTx: true, GasLimit: 10_000}, full},
+		{"regular message", bluebird, &Message{GasLimit: 10_000}, full},
+	} {
+		if have := depositIntrinsicGas(&config, tc.time, tc.msg, full); have != tc.want {
+			t.Errorf("%s: intrinsic gas mismatch: have %d, want %d", tc.name, have, tc.want)
+		}
+	}
+	if full != params.TxGas+uint64(len(data))*params.TxDataNonZeroGasEIP2028 {
+		t.Errorf("regular intrinsic gas mismatch: have %d", full)
+	}
+}
+
+// TestDepositTxV2BelowIntrinsicGas checks that from Bluebird onwards, a Bluebird
+// deposit declaring less than the intrinsic gas is not forced up to the 21000 base,
+// but executes having consumed its declared gas. Before Bluebird it is included as
+// a failed deposit.
+func TestDepositTxV2BelowIntrinsicGas(t *testing.T) {
+	var (
+		zero   = uint64(0)
+		sender = common.Address{0x5e, 0xde}
+		gas    = uint64(10_000)
+	)
+	header := &types.Header{
+		Number:     big.NewInt(1),
+		Time:       1,
+		GasLimit:   30_000_000,
+		BaseFee:    big.NewInt(1),
+		Difficulty: common.Big0,
+	}
+	for _, bluebird := range []bool{false, true} {
+		config := *params.OptimismTestConfig
+		config.BedrockBlock = big.NewInt(0)
+		config.RegolithTime = &zero
+		want := types.ReceiptStatusFailed
+		if bluebird {
+			config.BluebirdTime = &zero
+			want = types.ReceiptStatusSuccessful
+		}
+		// Mint enough for the deposit to pay for its gas
+		opts := []deposittest.Option{deposittest.WithFrom(sender), deposittest.WithGas(gas), deposittest.WithMint(big.NewInt(params.Ether))}
+		for i, deposit := range []*types.Transaction{deposittest.NewTx(opts...), deposittest.NewTx(append(opts, deposittest.WithNonce(0))...)} {
+			statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+			var (
+				gp      = new(GasPool).AddGas(header.GasLimit)
+				usedGas uint64
+			)
+			statedb.SetTxContext(deposit.Hash(), 0)
+			receipt, err := ApplyTransaction(&config, nil, &common.Address{}, gp, statedb, header, deposit, &usedGas, vm.Config{})
+			if err != nil {
+				t.Fatalf("bluebird %v, deposit %d: deposit below intrinsic gas rejected: %v", bluebird, i, err)
+			}
+			if receipt.Status != want {
+				t.Errorf("bluebird %v, deposit %d: receipt status mismatch: have %d, want %d", bluebird, i, receipt.Status, want)
+			}
+			if bluebird && receipt.GasUsed != gas {
+				t.Errorf("bluebird %v, deposit %d: gas used mismatch: have %d, want %d", bluebird, i, receipt.GasUsed, gas)
+			}
+			if receipt.GasUsed > gas {
+				t.Errorf("bluebird %v, deposit %d: gas used exceeds declared gas: have %d, declared %d", bluebird, i, receipt.GasUsed, gas)
+			}
+		}
+	}
+}
+
+// TestDepositReceiptVersionAnnotation checks that a receipt version annotated by
+// derivation ends up in the deposit's receipt, overriding the fork default, while
+// leaving the transaction hash untouched.
+func TestDepositReceiptVersionAnnotation(t *testing.T) {
+	var (
+		zero   = uint64(0)
+		sender = common.Address{0x5e, 0xde}
+		header = &types.Header{
+			Number:     big.NewInt(1),
+			Time:       1,
+			GasLimit:   30_000_000,
+			BaseFee:    big.NewInt(1),
+			Difficulty: common.Big0,
+		}
+		deposit = types.NewTx(&types.DepositTxV2{DepositTx: types.DepositTx{
+			SourceHash: common.HexToHash("0xdeadbeef"),
+			From:       sender,
+			To:         &sender,
+			Value:      common.Big0,
+			Gas:        100_000,
+		}})
+	)
+	apply := func(config *params.ChainConfig, tx *types.Transaction) *types.Receipt {
+		t.Helper()
+
+		statedb, _ := state.New(types.EmptyRootHash, state.NewDatabase(rawdb.NewMemoryDatabase()), nil)
+		var (
+			gp      = new(GasPool).AddGas(header.GasLimit)
+			usedGas uint64
+		)
+		statedb.SetTxContext(tx.Hash(), 0)
+		receipt, err := ApplyTransaction(config, nil, &common.Address{}, gp, statedb, header, tx, &usedGas, vm.Config{})
+		if err != nil {
+			t.Fatalf("failed to apply deposit: %v", err)
+		}
+		return receipt
+	}
+	for _, canyon := range []bool{false, true} {
+		config := *params.OptimismTestConfig
+		config.BedrockBlock = big.NewInt(0)
+		config.RegolithTime = &zero
+		config.CanyonTime = nil
+		if canyon {
+			config.CanyonTime = &zero
+		}
+		var want *uint64
+		if canyon {
+			version := types.CanyonDepositReceiptVersion
+			want = &version
+		}
+		if have := apply(&config, deposit).DepositReceiptVersion; !reflect.DeepEqual(have, want) {
+			t.Errorf("canyon %v: default receipt version mismatch: have %v, want %v", canyon, have, want)
+		}
+		for _, version := range []uint64{types.CanyonDepositReceiptVersion, types.CanyonDepositReceiptVersion + 1} {
+			annotated, err := deposit.WithDepositReceiptVersion(version)
+			if err != nil {
+				t.Fatalf("failed to set receipt version: %v", err)
+			}
+			receipt := apply(&config, annotated)
+			if have := receipt.DepositReceiptVersion; have == nil || *have != version {
+				t.Errorf("canyon %v, version %d: receipt version mismatch: have %v, want %d", canyon, version, have, version)
+			}
+			if receipt.TxHash != deposit.Hash() {
+				t.Errorf("canyon %v, version %d: receipt tx hash mismatch: have %v, want %v", canyon, version, receipt.TxHash, deposit.Hash())
+			}
+		}
+	}
+}
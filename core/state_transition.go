@@ -150,6 +150,7 @@ type Message struct {
 
 	IsSystemTx     bool                 // IsSystemTx indicates the message, if also a deposit, does not emit gas usage.
 	IsDepositTx    bool                 // IsDepositTx indicates the message is force-included and can persist a mint.
+	IsDepositTxV2  bool                 // IsDepositTxV2 indicates the message, if also a deposit, is a Bluebird deposit.
 	Mint           *big.Int             // Mint is the amount to mint before EVM processing, or nil if there is no minting.
 	RollupCostData types.RollupCostData // RollupCostData caches data to compute the fee we charge for data availability
 }
@@ -168,6 +169,7 @@ func TransactionToMessage(tx *types.Transaction, s types.Signer, baseFee *big.In
 		AccessList:     tx.AccessList(),
 		IsSystemTx:     tx.IsSystemTx(),
 		IsDepositTx:    tx.IsDepositTx(),
+		IsDepositTxV2:  tx.Type() == types.DepositTxV2Type,
 		Mint:           tx.Mint(),
 		RollupCostData: tx.RollupCostData(),
 
@@ -514,6 +516,7 @@ func (st *StateTransition) innerTransitionDb() (*ExecutionResult, error) {
 	if err != nil {
 		return nil, err
 	}
+	gas = depositIntrinsicGas(st.evm.ChainConfig(), st.evm.Context.Time, msg, gas)
 	if st.gasRemaining < gas {
 		return nil, fmt.Errorf("%w: have %d, want %d", ErrIntrinsicGas, st.gasRemaining, gas)
 	}
@@ -647,6 +650,18 @@ func (st *StateTransition) innerTransitionDb() (*ExecutionResult, error) {
 	}, nil
 }
 
+// depositIntrinsicGas caps the intrinsic gas of a Bluebird deposit at the gas it
+// declares. Deposits are force-included and bypass the usual gas rules, so from
+// Bluebird onwards a V2 deposit declaring less than the 21000 base is not failed
+// for it, but consumes its declared gas as intrinsic gas instead. Other messages
+// are charged in full.
+func depositIntrinsicGas(config *params.ChainConfig, time uint64, msg *Message, gas uint64) uint64 {
+	if !msg.IsDepositTx || !msg.IsDepositTxV2 || !config.IsBluebird(time) {
+		return gas
+	}
+	return min(gas, msg.GasLimit)
+}
+
 func (st *StateTransition) refundGas(refundQuotient uint64) uint64 {
 	// Apply refund counter, capped to a refund quotient
 	refund := st.gasUsed() / refundQuotient
@@ -349,6 +349,18 @@ func (b *Block) Transaction(hash common.Hash) *Transaction {
 	return nil
 }
 
+// DepositTxCount returns the number of deposit transactions in the block, of any
+// deposit version.
+func (b *Block) DepositTxCount() int {
+	var count int
+	for _, tx := range b.transactions {
+		if tx.IsDepositTx() {
+			count++
+		}
+	}
+	return count
+}
+
 // Header returns the block header (as a copy).
 func (b *Block) Header() *Header {
 	return CopyHeader(b.header)
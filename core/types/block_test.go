@@ -317,3 +317,23 @@ func TestRlpDecodeParentHash(t *testing.T) {
 		}
 	}
 }
+
+func TestBlockDepositTxCount(t *testing.T) {
+	dep := DepositTx{SourceHash: common.HexToHash("0xdeadbeef"), Value: big.NewInt(0), Gas: 50000}
+	txs := Transactions{
+		NewTx(&LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000}),
+		NewTx(&dep),
+		NewTx(&DepositTxV2{dep}),
+		NewTx(&DynamicFeeTx{Nonce: 2, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1), Gas: 21000}),
+		{inner: &depositTxV2WithNonce{DepositTxV2: DepositTxV2{dep}, EffectiveNonce: 7}},
+		{inner: &depositTxWithNonce{DepositTx: dep, EffectiveNonce: 8}},
+	}
+	block := NewBlock(&Header{Number: big.NewInt(1)}, &Body{Transactions: txs}, nil, blocktest.NewHasher())
+	if have, want := block.DepositTxCount(), 4; have != want {
+		t.Errorf("deposit count mismatch: have %d, want %d", have, want)
+	}
+	empty := NewBlock(&Header{Number: big.NewInt(1)}, nil, nil, blocktest.NewHasher())
+	if have := empty.DepositTxCount(); have != 0 {
+		t.Errorf("empty block deposit count mismatch: have %d, want 0", have)
+	}
+}
@@ -0,0 +1,68 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package types
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrDepositSourceHashMismatch is returned if the source hash of a deposit does
+// not match the one recomputed from its derivation inputs.
+var ErrDepositSourceHashMismatch = errors.New("deposit source hash mismatch")
+
+// Source hash domains of the deposit transaction spec.
+const (
+	UserDepositSourceDomain   = 0
+	L1InfoDepositSourceDomain = 1
+)
+
+// UserDepositSourceHash computes the source hash of a deposit derived from the
+// user-deposit log at logIndex within the L1 block l1BlockHash.
+func UserDepositSourceHash(l1BlockHash common.Hash, logIndex uint64) common.Hash {
+	return depositSourceHash(UserDepositSourceDomain, l1BlockHash, logIndex)
+}
+
+// VerifyUserDepositSourceHash checks that the source hash of the user deposit tx
+// matches the one derived from the user-deposit log at logIndex within the L1
+// block l1BlockHash.
+func VerifyUserDepositSourceHash(tx *DepositTxV2, l1BlockHash common.Hash, logIndex uint64) error {
+	if want := UserDepositSourceHash(l1BlockHash, logIndex); tx.SourceHash != want {
+		return fmt.Errorf("%w: have %v, want %v", ErrDepositSourceHashMismatch, tx.SourceHash, want)
+	}
+	return nil
+}
+
+// L1InfoDepositSourceHash computes the source hash of the L1 info system deposit
+// included in the L2 block with the given sequence number of epoch l1BlockHash.
+func L1InfoDepositSourceHash(l1BlockHash common.Hash, seqNumber uint64) common.Hash {
+	return depositSourceHash(L1InfoDepositSourceDomain, l1BlockHash, seqNumber)
+}
+
+// depositSourceHash implements keccak256(bytes32(domain) ++ keccak256(l1BlockHash ++ bytes32(index))).
+func depositSourceHash(domain uint64, l1BlockHash common.Hash, index uint64) common.Hash {
+	var domainInput, indexInput common.Hash
+	binary.BigEndian.PutUint64(domainInput[24:], domain)
+	binary.BigEndian.PutUint64(indexInput[24:], index)
+
+	depositIDHash := crypto.Keccak256(l1BlockHash[:], indexInput[:])
+	return crypto.Keccak256Hash(domainInput[:], depositIDHash)
+}
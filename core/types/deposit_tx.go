@@ -19,16 +19,42 @@ package types
 import (
 	"bytes"
 	"math/big"
+	"slices"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rlp"
 )
 
+// Deposit transaction types. They count down from 0x7E so that they cannot
+// collide with Ethereum transaction types, which count up from 0x00.
 const (
 	DepositTxType   = 0x7E // Legacy deposits (pre-Bluebird)
 	DepositTxV2Type = 0x7D // Bluebird deposits (exclude Mint from hash)
 )
 
+// isDepositTxType reports whether t is the type of a deposit transaction, of any
+// version. It must list the same types as newDepositTxData.
+func isDepositTxType(t byte) bool {
+	switch t {
+	case DepositTxType, DepositTxV2Type:
+		return true
+	}
+	return false
+}
+
+// newDepositTxData returns an empty deposit payload of the given type, or nil if t
+// is not a deposit type. Adding a deposit version only requires a new case here
+// and in isDepositTxType.
+func newDepositTxData(t byte) TxData {
+	switch t {
+	case DepositTxType:
+		return new(DepositTx)
+	case DepositTxV2Type:
+		return new(DepositTxV2)
+	}
+	return nil
+}
+
 type DepositTx struct {
 	// SourceHash uniquely identifies the source of the deposit
 	SourceHash common.Hash
@@ -83,14 +109,21 @@ func (tx *DepositTx) nonce() uint64          { return 0 }
 func (tx *DepositTx) to() *common.Address    { return tx.To }
 func (tx *DepositTx) isSystemTx() bool       { return tx.IsSystemTransaction }
 
+// effectiveGasPrice returns the base fee of the including block, or zero for
+// blocks without one.
 func (tx *DepositTx) effectiveGasPrice(dst *big.Int, baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return dst.SetUint64(0)
+	}
 	return dst.Set(baseFee)
 }
 
 func (tx *DepositTx) effectiveNonce() *uint64 { return nil }
 
+// rawSignatureValues returns fresh zero values, since deposits carry no
+// signature and callers may mutate the results.
 func (tx *DepositTx) rawSignatureValues() (v, r, s *big.Int) {
-	return common.Big0, common.Big0, common.Big0
+	return new(big.Int), new(big.Int), new(big.Int)
 }
 
 func (tx *DepositTx) setSignatureValues(chainID, v, r, s *big.Int) {
@@ -104,3 +137,23 @@ func (tx *DepositTx) encode(b *bytes.Buffer) error {
 func (tx *DepositTx) decode(input []byte) error {
 	return rlp.DecodeBytes(input, tx)
 }
+
+// SortDepositsBySourceHash sorts txs in place so that deposit transactions come
+// first, in ascending byte order of their source hashes, followed by all other
+// transactions in their original order. It gives block builders and derivation a
+// deterministic deposit order that does not depend on how deposits were gathered.
+func SortDepositsBySourceHash(txs []*Transaction) {
+	slices.SortStableFunc(txs, func(a, b *Transaction) int {
+		switch aDep, bDep := a.IsDepositTx(), b.IsDepositTx(); {
+		case aDep && bDep:
+			ah, bh := a.SourceHash(), b.SourceHash()
+			return bytes.Compare(ah[:], bh[:])
+		case aDep:
+			return -1
+		case bDep:
+			return 1
+		default:
+			return 0
+		}
+	})
+}
@@ -16,9 +16,28 @@
 
 package types
 
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	ErrDepositNegativeValue = errors.New("deposit transaction value is negative")
+	ErrDepositNegativeMint  = errors.New("deposit transaction mint is negative")
+)
+
 // DepositTxV2 embeds DepositTx to inherit all fields and methods
 type DepositTxV2 struct{ DepositTx }
 
+var _ TxData = (*DepositTxV2)(nil)
+
 // txType identifies this as a V2 deposit
 func (tx *DepositTxV2) txType() byte { return DepositTxV2Type }
 
@@ -26,4 +45,166 @@ func (tx *DepositTxV2) txType() byte { return DepositTxV2Type }
 func (tx *DepositTxV2) copy() TxData {
 	depCopy := tx.DepositTx.copy().(*DepositTx) // deep-copy from the embedded value
 	return &DepositTxV2{DepositTx: *depCopy}
-}
\ No newline at end of file
+}
+
+// decode decodes the RLP payload and rejects amounts that are negative. The rlp
+// package already rejects non-canonical integers, so a Mint or
+// Value with leading zero bytes cannot alias a canonical deposit's hash.
+func (tx *DepositTxV2) decode(input []byte) error {
+	if err := rlp.DecodeBytes(input, tx); err != nil {
+		return err
+	}
+	return tx.validateAmounts()
+}
+
+// validateAmounts checks that neither Value nor Mint is negative. RLP cannot
+// carry negative integers, but JSON-decoded and hand-built payloads can.
+func (tx *DepositTxV2) validateAmounts() error {
+	if tx.Value != nil && tx.Value.Sign() < 0 {
+		return ErrDepositNegativeValue
+	}
+	if tx.Mint != nil && tx.Mint.Sign() < 0 {
+		return ErrDepositNegativeMint
+	}
+	return nil
+}
+
+// HashKey returns the hash of a transaction wrapping the deposit. As the hash does
+// not cover Mint, deposits differing only in their mint share a key, which makes
+// it the canonical key for deduplicating deposits derived from the same L1 event.
+func (tx *DepositTxV2) HashKey() common.Hash {
+	// Copy the deposit and zero out the Mint field only
+	d := tx.DepositTx
+	d.Mint = nil
+	return prefixedRlpHash(DepositTxV2Type, &d)
+}
+
+// Equal reports whether the two deposits carry identical field values. Unlike
+// the transaction hash, a nil Mint is considered distinct from a zero Mint.
+func (tx *DepositTxV2) Equal(other *DepositTxV2) bool {
+	if tx == nil || other == nil {
+		return tx == other
+	}
+	return tx.SourceHash == other.SourceHash &&
+		tx.From == other.From &&
+		equalAddressPtr(tx.To, other.To) &&
+		equalBigPtr(tx.Mint, other.Mint) &&
+		equalBigPtr(tx.Value, other.Value) &&
+		tx.Gas == other.Gas &&
+		tx.IsSystemTransaction == other.IsSystemTransaction &&
+		bytes.Equal(tx.Data, other.Data)
+}
+
+// String returns a compact, stable summary of the deposit for debugging. The data
+// is summarized by its length only.
+func (tx *DepositTxV2) String() string {
+	return "DepositTxV2{" + tx.fieldsString() + "}"
+}
+
+// fieldsString formats the deposit fields shared by the bare and nonce-wrapped
+// string representations.
+func (tx *DepositTxV2) fieldsString() string {
+	to, mint := "nil", "nil"
+	if tx.To != nil {
+		to = tx.To.Hex()
+	}
+	if tx.Mint != nil {
+		mint = tx.Mint.String()
+	}
+	return fmt.Sprintf("sourceHash: %s, from: %s, to: %s, mint: %s, value: %v, gas: %d, system: %t, data: %d bytes",
+		tx.SourceHash.Hex(), tx.From.Hex(), to, mint, tx.Value, tx.Gas, tx.IsSystemTransaction, len(tx.Data))
+}
+
+// equalAddressPtr reports whether two optional addresses are both unset or
+// both set to the same address.
+func equalAddressPtr(a, b *common.Address) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// equalBigPtr reports whether two optional integers are both unset or both set
+// to the same value.
+func equalBigPtr(a, b *big.Int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Cmp(b) == 0
+}
+
+// encodeInto resets w and writes the same payload as encode into it, allowing a
+// pooled buffer to be reused across many deposits.
+func (tx *DepositTxV2) encodeInto(w *bytes.Buffer) error {
+	w.Reset()
+	return tx.encode(w)
+}
+
+// EncodeRLP implements rlp.Encoder. The embedded DepositTx would otherwise be
+// encoded as a nested list, diverging from the flat typed-envelope payload.
+func (tx *DepositTxV2) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, &tx.DepositTx)
+}
+
+// DecodeRLP implements rlp.Decoder, mirroring EncodeRLP.
+func (tx *DepositTxV2) DecodeRLP(s *rlp.Stream) error {
+	return s.Decode(&tx.DepositTx)
+}
+
+// MarshalBinary returns the typed transaction envelope encoding of the deposit,
+// matching Transaction.MarshalBinary for a transaction wrapping it.
+func (tx *DepositTxV2) MarshalBinary() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte(DepositTxV2Type)
+	if err := tx.encode(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// UnmarshalBinary decodes the typed transaction envelope encoding of a deposit.
+func (tx *DepositTxV2) UnmarshalBinary(b []byte) error {
+	if len(b) <= 1 {
+		return errShortTypedTx
+	}
+	if b[0] != DepositTxV2Type {
+		return ErrInvalidTxType
+	}
+	return tx.decode(b[1:])
+}
+
+// DecodeDepositTxV2Hex decodes the hex-encoded typed transaction envelope of a
+// deposit, with or without a 0x prefix, as produced by tooling such as cast or
+// eth_getRawTransactionByHash.
+func DecodeDepositTxV2Hex(s string) (*DepositTxV2, error) {
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		s = s[2:]
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid deposit hex: %w", err)
+	}
+	tx := new(DepositTxV2)
+	if err := tx.UnmarshalBinary(b); err != nil {
+		return nil, fmt.Errorf("invalid deposit encoding: %w", err)
+	}
+	return tx, nil
+}
+
+// EncodeDepositTxV2Batch returns the typed transaction encoding of each deposit,
+// in order. A single scratch buffer is reused across all deposits.
+func EncodeDepositTxV2Batch(txs []*DepositTxV2) ([][]byte, error) {
+	var (
+		buf bytes.Buffer
+		out = make([][]byte, len(txs))
+	)
+	for i, tx := range txs {
+		buf.Reset()
+		buf.WriteByte(DepositTxV2Type)
+		if err := tx.encode(&buf); err != nil {
+			return nil, err
+		}
+		out[i] = common.CopyBytes(buf.Bytes())
+	}
+	return out, nil
+}
@@ -18,38 +18,114 @@ package types
 
 import (
 	"bytes"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"math/big"
+	"math/rand"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
+// testDepositAddr is the sender and recipient of the fixture deposits.
+var testDepositAddr = common.HexToAddress("0x1234567890123456789012345678901234567890")
+
+// testDeposit collects the fixture settings applied by depositOpts.
+type testDeposit struct {
+	dep   *DepositTxV2
+	nonce *uint64
+}
+
+// depositOpt customizes a fixture deposit built by newTestDepositTxV2.
+type depositOpt func(*testDeposit)
+
+// WithSourceHash sets the source hash of the fixture deposit.
+func WithSourceHash(hash common.Hash) depositOpt {
+	return func(d *testDeposit) { d.dep.SourceHash = hash }
+}
+
+// WithMint sets the mint of the fixture deposit, nil meaning none.
+func WithMint(mint *big.Int) depositOpt {
+	return func(d *testDeposit) { d.dep.Mint = mint }
+}
+
+// WithValue sets the value transferred by the fixture deposit.
+func WithValue(value *big.Int) depositOpt {
+	return func(d *testDeposit) { d.dep.Value = value }
+}
+
+// WithGas sets the gas limit of the fixture deposit.
+func WithGas(gas uint64) depositOpt {
+	return func(d *testDeposit) { d.dep.Gas = gas }
+}
+
+// WithData sets the calldata of the fixture deposit.
+func WithData(data []byte) depositOpt {
+	return func(d *testDeposit) { d.dep.Data = data }
+}
+
+// WithNilTo turns the fixture deposit into a contract creation.
+func WithNilTo() depositOpt {
+	return func(d *testDeposit) { d.dep.To = nil }
+}
+
+// WithSystem marks the fixture deposit as a system deposit.
+func WithSystem() depositOpt {
+	return func(d *testDeposit) { d.dep.IsSystemTransaction = true }
+}
+
+// WithNonce attaches an effective nonce to the transaction built by
+// newTestDepositTxV2Tx. It has no effect on newTestDepositTxV2.
+func WithNonce(nonce uint64) depositOpt {
+	return func(d *testDeposit) { d.nonce = &nonce }
+}
+
+func buildTestDeposit(opts []depositOpt) *testDeposit {
+	to := testDepositAddr
+	d := &testDeposit{dep: &DepositTxV2{DepositTx{
+		SourceHash: common.HexToHash("0xdeadbeef"),
+		From:       testDepositAddr,
+		To:         &to,
+		Mint:       big.NewInt(1000),
+		Value:      big.NewInt(2000),
+		Gas:        50000,
+		Data:       []byte("test data"),
+	}}}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// newTestDepositTxV2 creates a user deposit with fixed fixture values, tweaked
+// by the given options. The deposittest package provides the same fixture to
+// tests outside of this package.
+func newTestDepositTxV2(opts ...depositOpt) *DepositTxV2 {
+	return buildTestDeposit(opts).dep
+}
+
+// newTestDepositTxV2Tx wraps a fixture deposit into a transaction, carrying the
+// effective nonce if one was set with WithNonce.
+func newTestDepositTxV2Tx(opts ...depositOpt) *Transaction {
+	d := buildTestDeposit(opts)
+	if d.nonce != nil {
+		return &Transaction{inner: &depositTxV2WithNonce{DepositTxV2: *d.dep, EffectiveNonce: *d.nonce}}
+	}
+	return &Transaction{inner: d.dep}
+}
+
 func TestDepositTxV2Hash(t *testing.T) {
 	// Create two identical transactions, one V1 and one V2
-	addr := common.HexToAddress("0x1234567890123456789012345678901234567890")
-	
-	v1 := &Transaction{inner: &DepositTx{
-		SourceHash:          common.HexToHash("0xdeadbeef"),
-		From:                addr,
-		To:                  &addr,
-		Mint:                big.NewInt(1000),
-		Value:               big.NewInt(2000),
-		Gas:                 50000,
-		IsSystemTransaction: true,
-		Data:                []byte("test data"),
-	}}
-	
-	v2 := &Transaction{inner: &DepositTxV2{DepositTx{
-		SourceHash:          common.HexToHash("0xdeadbeef"),
-		From:                addr,
-		To:                  &addr,
-		Mint:                big.NewInt(1000),
-		Value:               big.NewInt(2000),
-		Gas:                 50000,
-		IsSystemTransaction: true,
-		Data:                []byte("test data"),
-	}}}
+	dep := newTestDepositTxV2(WithSystem())
+	v1 := &Transaction{inner: &dep.DepositTx}
+	v2 := &Transaction{inner: dep}
 	
 	// V1 and V2 should have different hashes
 	hash1 := v1.Hash()
@@ -60,16 +136,7 @@ func TestDepositTxV2Hash(t *testing.T) {
 	}
 	
 	// Create V2 without Mint (but same IsSystemTransaction)
-	v2NoMint := &Transaction{inner: &DepositTxV2{DepositTx{
-		SourceHash:          common.HexToHash("0xdeadbeef"),
-		From:                addr,
-		To:                  &addr,
-		Mint:                nil,
-		Value:               big.NewInt(2000),
-		Gas:                 50000,
-		IsSystemTransaction: true,  // Same as original
-		Data:                []byte("test data"),
-	}}}
+	v2NoMint := newTestDepositTxV2Tx(WithSystem(), WithMint(nil))
 	
 	// V2 with Mint should hash the same as V2 without Mint
 	hash2NoMint := v2NoMint.Hash()
@@ -79,16 +146,7 @@ func TestDepositTxV2Hash(t *testing.T) {
 	}
 	
 	// Create V2 with different IsSystemTransaction - should have different hash
-	v2DiffSystem := &Transaction{inner: &DepositTxV2{DepositTx{
-		SourceHash:          common.HexToHash("0xdeadbeef"),
-		From:                addr,
-		To:                  &addr,
-		Mint:                big.NewInt(1000),
-		Value:               big.NewInt(2000),
-		Gas:                 50000,
-		IsSystemTransaction: false,  // Different from original
-		Data:                []byte("test data"),
-	}}}
+	v2DiffSystem := newTestDepositTxV2Tx()
 	
 	hash2DiffSystem := v2DiffSystem.Hash()
 	
@@ -97,6 +155,30 @@ func TestDepositTxV2Hash(t *testing.T) {
 	}
 }
 
+func TestDepositTxV2HashMatchesEncoding(t *testing.T) {
+	for _, mint := range []*big.Int{nil, big.NewInt(1000)} {
+		inner := *newTestDepositTxV2(WithMint(mint))
+		for _, tx := range []*Transaction{
+			NewTx(&inner),
+			{inner: &depositTxV2WithNonce{DepositTxV2: inner, EffectiveNonce: 7}},
+		} {
+			// The hash covers the canonical encoding with the mint left out
+			noMint := NewTx(&DepositTxV2{inner.DepositTx})
+			noMint.inner.(*DepositTxV2).Mint = nil
+			enc, err := noMint.MarshalBinary()
+			if err != nil {
+				t.Fatalf("failed to encode deposit: %v", err)
+			}
+			if want := crypto.Keccak256Hash(enc); tx.Hash() != want {
+				t.Errorf("mint %v, %T: hash mismatch: have %v, want %v", mint, tx.inner, tx.Hash(), want)
+			}
+			if enc, _ := tx.MarshalBinary(); mint == nil && crypto.Keccak256Hash(enc) != tx.Hash() {
+				t.Errorf("%T: hash differs from encoding without a mint", tx.inner)
+			}
+		}
+	}
+}
+
 func TestDepositTxV2Type(t *testing.T) {
 	tx := &Transaction{inner: &DepositTxV2{}}
 	
@@ -110,17 +192,7 @@ func TestDepositTxV2Type(t *testing.T) {
 }
 
 func TestDepositTxV2Copy(t *testing.T) {
-	addr := common.HexToAddress("0x1234567890123456789012345678901234567890")
-	original := &DepositTxV2{DepositTx{
-		SourceHash:          common.HexToHash("0xdeadbeef"),
-		From:                addr,
-		To:                  &addr,
-		Mint:                big.NewInt(1000),
-		Value:               big.NewInt(2000),
-		Gas:                 50000,
-		IsSystemTransaction: true,
-		Data:                []byte("test data"),
-	}}
+	original := newTestDepositTxV2(WithSystem())
 	
 	// Test copy
 	copied := original.copy()
@@ -146,20 +218,50 @@ func TestDepositTxV2Copy(t *testing.T) {
 	}
 }
 
+func TestDepositTxV2HashKey(t *testing.T) {
+	a := newTestDepositTxV2()
+	b := newTestDepositTxV2(WithMint(big.NewInt(5000)))
+	c := newTestDepositTxV2(WithMint(nil))
+	if a.HashKey() != b.HashKey() || a.HashKey() != c.HashKey() {
+		t.Errorf("key depends on mint: %v, %v, %v", a.HashKey(), b.HashKey(), c.HashKey())
+	}
+	if have, want := a.HashKey(), NewTx(a).Hash(); have != want {
+		t.Errorf("key mismatch with transaction hash: have %v, want %v", have, want)
+	}
+	if a.Mint == nil {
+		t.Error("computing the key modified the deposit")
+	}
+	// Any other field still distinguishes deposits
+	if d := newTestDepositTxV2(WithSystem()); d.HashKey() == a.HashKey() {
+		t.Error("deposits differing in the system flag share a key")
+	}
+}
+
+func TestDepositTxV2WithNonceCopy(t *testing.T) {
+	original := newTestDepositTxV2Tx(WithSystem(), WithNonce(42)).inner.(*depositTxV2WithNonce)
+
+	copied, ok := original.copy().(*depositTxV2WithNonce)
+	if !ok {
+		t.Fatalf("copy() returned %T, want *depositTxV2WithNonce", original.copy())
+	}
+	if copied.EffectiveNonce != original.EffectiveNonce {
+		t.Errorf("nonce mismatch: have %d, want %d", copied.EffectiveNonce, original.EffectiveNonce)
+	}
+	if copied.Mint == original.Mint || copied.Value == original.Value || copied.To == original.To {
+		t.Error("copy shares pointers with the original")
+	}
+	if !copied.DepositTxV2.Equal(&original.DepositTxV2) {
+		t.Error("copied deposit fields mismatch")
+	}
+	// Transactions built from a wrapper keep the nonce
+	if nonce := NewTx(original).EffectiveNonce(); nonce == nil || *nonce != original.EffectiveNonce {
+		t.Errorf("effective nonce lost by NewTx: have %v, want %d", nonce, original.EffectiveNonce)
+	}
+}
+
 func TestDepositTxV2Marshalling(t *testing.T) {
-	addr := common.HexToAddress("0x1234567890123456789012345678901234567890")
-	
 	// Test transaction without nonce
-	tx1 := &Transaction{inner: &DepositTxV2{DepositTx{
-		SourceHash:          common.HexToHash("0xdeadbeef"),
-		From:                addr,
-		To:                  &addr,
-		Mint:                big.NewInt(1000),
-		Value:               big.NewInt(2000),
-		Gas:                 50000,
-		IsSystemTransaction: true,
-		Data:                []byte("test data"),
-	}}}
+	tx1 := newTestDepositTxV2Tx(WithSystem())
 	
 	// Marshal to JSON
 	jsonData, err := tx1.MarshalJSON()
@@ -187,7 +289,7 @@ func TestDepositTxV2Marshalling(t *testing.T) {
 	if v2.SourceHash != common.HexToHash("0xdeadbeef") {
 		t.Error("SourceHash mismatch after unmarshalling")
 	}
-	if v2.From != addr {
+	if v2.From != testDepositAddr {
 		t.Error("From address mismatch after unmarshalling")
 	}
 	if v2.Mint.Cmp(big.NewInt(1000)) != 0 {
@@ -195,9 +297,118 @@ func TestDepositTxV2Marshalling(t *testing.T) {
 	}
 }
 
-func TestDepositTxV2WithNonce(t *testing.T) {
-	// Create JSON with nonce
-	jsonStr := `{
+func TestDepositJSONTypeRouting(t *testing.T) {
+	enc, err := json.Marshal(newTestDepositTxV2Tx())
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(enc, &fields); err != nil {
+		t.Fatalf("failed to unmarshal fields: %v", err)
+	}
+	if fields["type"] != "0x7d" {
+		t.Fatalf("type encoding mismatch: have %v, want 0x7d", fields["type"])
+	}
+	for _, tc := range []struct {
+		typ  string
+		want TxData
+	}{
+		{"0x7d", &DepositTxV2{}},
+		{"0x7e", &DepositTx{}},
+		{"125", &DepositTxV2{}},
+		{"126", &DepositTx{}},
+	} {
+		fields["type"] = tc.typ
+		input, _ := json.Marshal(fields)
+		var tx Transaction
+		if err := json.Unmarshal(input, &tx); err != nil {
+			t.Fatalf("type %s: failed to unmarshal: %v", tc.typ, err)
+		}
+		if have, want := fmt.Sprintf("%T", tx.inner), fmt.Sprintf("%T", tc.want); have != want {
+			t.Errorf("type %s: inner type mismatch: have %s, want %s", tc.typ, have, want)
+		}
+	}
+	for _, typ := range []string{"0x7g", "12x", ""} {
+		fields["type"] = typ
+		input, _ := json.Marshal(fields)
+		if err := json.Unmarshal(input, new(Transaction)); err == nil {
+			t.Errorf("type %q: invalid type accepted", typ)
+		}
+	}
+}
+
+// TestDepositTxV2TxData calls every TxData method on populated bare and
+// nonce-wrapped V2 deposits, locking in that none of them panics and that the
+// signature methods stay no-ops.
+func TestDepositTxV2TxData(t *testing.T) {
+	dep := newTestDepositTxV2(WithSystem())
+	for _, tc := range []struct {
+		name  string
+		inner TxData
+		nonce uint64
+	}{
+		{"bare", dep, 0},
+		{"wrapped", newTestDepositTxV2Tx(WithSystem(), WithNonce(7)).inner, 7},
+	} {
+		inner := tc.inner
+		if inner.txType() != DepositTxV2Type {
+			t.Errorf("%s: type mismatch: have %#x, want %#x", tc.name, inner.txType(), DepositTxV2Type)
+		}
+		if inner.copy().txType() != DepositTxV2Type {
+			t.Errorf("%s: copy type mismatch", tc.name)
+		}
+		if inner.chainID().Sign() != 0 {
+			t.Errorf("%s: nonzero chain id %v", tc.name, inner.chainID())
+		}
+		if inner.accessList() != nil {
+			t.Errorf("%s: non-nil access list", tc.name)
+		}
+		if !bytes.Equal(inner.data(), dep.Data) {
+			t.Errorf("%s: data mismatch", tc.name)
+		}
+		if inner.gas() != dep.Gas {
+			t.Errorf("%s: gas mismatch: have %d, want %d", tc.name, inner.gas(), dep.Gas)
+		}
+		if inner.gasPrice().Sign() != 0 || inner.gasTipCap().Sign() != 0 || inner.gasFeeCap().Sign() != 0 {
+			t.Errorf("%s: nonzero fee fields", tc.name)
+		}
+		if inner.value().Cmp(dep.Value) != 0 {
+			t.Errorf("%s: value mismatch: have %v, want %v", tc.name, inner.value(), dep.Value)
+		}
+		if inner.nonce() != tc.nonce {
+			t.Errorf("%s: nonce mismatch: have %d, want %d", tc.name, inner.nonce(), tc.nonce)
+		}
+		if to := inner.to(); to == nil || *to != testDepositAddr {
+			t.Errorf("%s: recipient mismatch: have %v, want %v", tc.name, to, testDepositAddr)
+		}
+		if !inner.isSystemTx() {
+			t.Errorf("%s: system flag lost", tc.name)
+		}
+		// Deposits are unsigned, so setting signature values is a no-op
+		inner.setSignatureValues(big.NewInt(1), big.NewInt(2), big.NewInt(3), big.NewInt(4))
+		if v, r, s := inner.rawSignatureValues(); v.Sign() != 0 || r.Sign() != 0 || s.Sign() != 0 {
+			t.Errorf("%s: signature values set: v %v, r %v, s %v", tc.name, v, r, s)
+		}
+		baseFee := big.NewInt(100)
+		if price := inner.effectiveGasPrice(new(big.Int), baseFee); price.Cmp(baseFee) != 0 {
+			t.Errorf("%s: effective gas price mismatch: have %v, want %v", tc.name, price, baseFee)
+		}
+		var buf bytes.Buffer
+		if err := inner.encode(&buf); err != nil {
+			t.Fatalf("%s: failed to encode: %v", tc.name, err)
+		}
+		dec := new(DepositTxV2)
+		if err := dec.decode(buf.Bytes()); err != nil {
+			t.Fatalf("%s: failed to decode: %v", tc.name, err)
+		}
+		if !dec.Equal(dep) {
+			t.Errorf("%s: deposit mismatch after round trip", tc.name)
+		}
+	}
+}
+
+// testDepositTxV2WithNonceJSON is the JSON encoding of a nonce-wrapped V2 deposit.
+const testDepositTxV2WithNonceJSON = `{
 		"type": "0x7d",
 		"sourceHash": "0x000000000000000000000000000000000000000000000000000000000000dead",
 		"from": "0x1234567890123456789012345678901234567890",
@@ -210,9 +421,11 @@ func TestDepositTxV2WithNonce(t *testing.T) {
 		"nonce": "0x42",
 		"hash": "0x0000000000000000000000000000000000000000000000000000000000000000"
 	}`
-	
+
+func TestDepositTxV2WithNonce(t *testing.T) {
+	// Create JSON with nonce
 	var tx Transaction
-	if err := json.Unmarshal([]byte(jsonStr), &tx); err != nil {
+	if err := json.Unmarshal([]byte(testDepositTxV2WithNonceJSON), &tx); err != nil {
 		t.Fatalf("Failed to unmarshal DepositTxV2 with nonce: %v", err)
 	}
 	
@@ -232,19 +445,36 @@ func TestDepositTxV2WithNonce(t *testing.T) {
 	}
 }
 
+// FuzzDepositTxV2JSON feeds arbitrary input to the JSON decoder of transactions,
+// which must never panic, and checks that any deposit it accepts can be hashed
+// and encoded again.
+func FuzzDepositTxV2JSON(f *testing.F) {
+	valid := testDepositTxV2WithNonceJSON
+	f.Add([]byte(valid))
+	f.Add([]byte(strings.Replace(valid, `"nonce": "0x42",`, "", 1)))
+	f.Add([]byte(strings.Replace(valid, `"0x3e8"`, `"-0x3e8"`, 1)))
+	f.Add([]byte(strings.Replace(valid, `"0x3e8"`, `null`, 1)))
+	f.Add([]byte(strings.Replace(valid, `"0x7d"`, `"125"`, 1)))
+	f.Add([]byte(strings.Replace(valid, `"0x1234567890123456789012345678901234567890",`, `"0x12",`, 1)))
+	f.Add([]byte(strings.Replace(valid, `true`, `"yes"`, 1)))
+	f.Add([]byte(valid[:len(valid)/2]))
+	f.Add([]byte(`{"type":"0x7d"}`))
+	f.Add([]byte(`{"type":"0x7d","nonce":"0xffffffffffffffffff"}`))
+
+	f.Fuzz(func(t *testing.T, input []byte) {
+		var tx Transaction
+		if err := tx.UnmarshalJSON(input); err != nil {
+			return
+		}
+		tx.Hash()
+		if _, err := tx.MarshalJSON(); err != nil {
+			t.Fatalf("failed to re-encode accepted transaction: %v", err)
+		}
+	})
+}
+
 func TestDepositTxV2RLP(t *testing.T) {
-	addr := common.HexToAddress("0x1234567890123456789012345678901234567890")
-	
-	tx := &DepositTxV2{DepositTx{
-		SourceHash:          common.HexToHash("0xdeadbeef"),
-		From:                addr,
-		To:                  &addr,
-		Mint:                big.NewInt(1000),
-		Value:               big.NewInt(2000),
-		Gas:                 50000,
-		IsSystemTransaction: true,
-		Data:                []byte("test data"),
-	}}
+	tx := newTestDepositTxV2(WithSystem())
 	
 	// Encode
 	var buf bytes.Buffer
@@ -271,20 +501,10 @@ func TestDepositTxV2RLP(t *testing.T) {
 }
 
 func TestDepositTxV2HelperMethods(t *testing.T) {
-	addr := common.HexToAddress("0x1234567890123456789012345678901234567890")
-	sourceHash := common.HexToHash("0xdeadbeef")
-	mint := big.NewInt(1000)
-	
-	tx := &Transaction{inner: &DepositTxV2{DepositTx{
-		SourceHash:          sourceHash,
-		From:                addr,
-		To:                  &addr,
-		Mint:                mint,
-		Value:               big.NewInt(2000),
-		Gas:                 50000,
-		IsSystemTransaction: true,
-		Data:                []byte("test data"),
-	}}}
+	sourceHash := common.HexToHash("0x1234")
+	mint := big.NewInt(5000)
+	
+	tx := newTestDepositTxV2Tx(WithSourceHash(sourceHash), WithMint(mint), WithSystem())
 	
 	// Test SourceHash()
 	if tx.SourceHash() != sourceHash {
@@ -332,24 +552,8 @@ func TestDepositTxV2Signing(t *testing.T) {
 }
 
 func TestDepositTxV2WithNonceHash(t *testing.T) {
-	addr := common.HexToAddress("0x1234567890123456789012345678901234567890")
-	
 	// Create a transaction with nonce wrapper
-	inner := &depositTxV2WithNonce{
-		DepositTxV2: DepositTxV2{DepositTx{
-			SourceHash:          common.HexToHash("0xdeadbeef"),
-			From:                addr,
-			To:                  &addr,
-			Mint:                big.NewInt(1000),
-			Value:               big.NewInt(2000),
-			Gas:                 50000,
-			IsSystemTransaction: true,
-			Data:                []byte("test data"),
-		}},
-		EffectiveNonce: 42,
-	}
-	
-	tx := &Transaction{inner: inner}
+	tx := newTestDepositTxV2Tx(WithSystem(), WithNonce(42))
 	
 	// Test that Hash works without panic
 	hash := tx.Hash()
@@ -371,24 +575,1292 @@ func TestDepositTxV2WithNonceHash(t *testing.T) {
 	
 	// Verify the hash excludes Mint field
 	// Create same tx without mint to compare
-	innerNoMint := &depositTxV2WithNonce{
-		DepositTxV2: DepositTxV2{DepositTx{
-			SourceHash:          common.HexToHash("0xdeadbeef"),
-			From:                addr,
-			To:                  &addr,
-			Mint:                nil, // No mint
-			Value:               big.NewInt(2000),
-			Gas:                 50000,
-			IsSystemTransaction: true,
-			Data:                []byte("test data"),
-		}},
-		EffectiveNonce: 42,
-	}
-	
-	txNoMint := &Transaction{inner: innerNoMint}
+	txNoMint := newTestDepositTxV2Tx(WithSystem(), WithMint(nil), WithNonce(42))
 	hashNoMint := txNoMint.Hash()
 	
 	if hash != hashNoMint {
 		t.Error("Hash should be the same regardless of Mint value")
 	}
-}
\ No newline at end of file
+}
+// assertDepositRoundTrips checks that a deposit survives the RLP, binary and JSON
+// encodings with its fields and hash intact. Only JSON is lossless: the effective
+// nonce of wrapped forms is not part of the consensus encoding, and a nil Mint
+// decodes from it as zero.
+func assertDepositRoundTrips(t *testing.T, inner TxData) {
+	t.Helper()
+
+	tx := &Transaction{inner: inner}
+	hash := tx.Hash()
+	if again := (&Transaction{inner: inner}).Hash(); again != hash {
+		t.Errorf("%T: hash not deterministic: have %v, want %v", inner, again, hash)
+	}
+	check := func(codec string, dec *Transaction, lossless bool) {
+		t.Helper()
+		haveMint, wantMint := dec.Mint(), tx.Mint()
+		if !lossless && wantMint == nil {
+			wantMint = new(big.Int)
+		}
+		if dec.Type() != tx.Type() {
+			t.Errorf("%T %s: type mismatch: have %d, want %d", inner, codec, dec.Type(), tx.Type())
+		}
+		if dec.Hash() != hash {
+			t.Errorf("%T %s: hash mismatch: have %v, want %v", inner, codec, dec.Hash(), hash)
+		}
+		haveFrom, _ := dec.depositFrom()
+		wantFrom, _ := tx.depositFrom()
+		if dec.SourceHash() != tx.SourceHash() || haveFrom != wantFrom ||
+			!equalAddressPtr(dec.To(), tx.To()) || !equalBigPtr(haveMint, wantMint) ||
+			dec.Value().Cmp(tx.Value()) != 0 || dec.Gas() != tx.Gas() ||
+			dec.IsSystemTx() != tx.IsSystemTx() || !bytes.Equal(dec.Data(), tx.Data()) {
+			t.Errorf("%T %s: field mismatch after round trip", inner, codec)
+		}
+		switch have, want := dec.EffectiveNonce(), tx.EffectiveNonce(); {
+		case !lossless && have != nil:
+			t.Errorf("%T %s: decoded transaction carries an effective nonce", inner, codec)
+		case lossless && (have == nil) != (want == nil):
+			t.Errorf("%T %s: effective nonce presence mismatch", inner, codec)
+		case lossless && have != nil && *have != *want:
+			t.Errorf("%T %s: effective nonce mismatch: have %d, want %d", inner, codec, *have, *want)
+		}
+	}
+
+	// RLP round trip, which must also re-encode to the same bytes
+	enc, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		t.Fatalf("%T: failed to RLP encode: %v", inner, err)
+	}
+	var rlpDec Transaction
+	if err := rlp.DecodeBytes(enc, &rlpDec); err != nil {
+		t.Fatalf("%T: failed to RLP decode: %v", inner, err)
+	}
+	check("rlp", &rlpDec, false)
+	if reenc, _ := rlp.EncodeToBytes(&rlpDec); !bytes.Equal(enc, reenc) {
+		t.Errorf("%T: RLP re-encoding mismatch:\nhave %x\nwant %x", inner, reenc, enc)
+	}
+
+	// Binary round trip, whose length must match the reported size
+	bin, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("%T: failed to marshal binary: %v", inner, err)
+	}
+	if size := tx.Size(); size != uint64(len(bin)) {
+		t.Errorf("%T: size mismatch: have %d, want %d", inner, size, len(bin))
+	}
+	var binDec Transaction
+	if err := binDec.UnmarshalBinary(bin); err != nil {
+		t.Fatalf("%T: failed to unmarshal binary: %v", inner, err)
+	}
+	check("binary", &binDec, false)
+
+	// JSON round trip, which preserves the effective nonce and a nil Mint
+	js, err := json.Marshal(tx)
+	if err != nil {
+		t.Fatalf("%T: failed to marshal JSON: %v", inner, err)
+	}
+	var jsonDec Transaction
+	if err := json.Unmarshal(js, &jsonDec); err != nil {
+		t.Fatalf("%T: failed to unmarshal JSON: %v", inner, err)
+	}
+	check("json", &jsonDec, true)
+}
+
+func TestDepositRoundTrips(t *testing.T) {
+	for _, creation := range []bool{false, true} {
+		for _, mint := range []*big.Int{nil, big.NewInt(1000)} {
+			opts := []depositOpt{WithMint(mint)}
+			if creation {
+				opts = append(opts, WithNilTo())
+			}
+			dep := newTestDepositTxV2(opts...).DepositTx
+			for _, inner := range []TxData{
+				&dep,
+				&depositTxWithNonce{DepositTx: dep, EffectiveNonce: 7},
+				&DepositTxV2{dep},
+				&depositTxV2WithNonce{DepositTxV2: DepositTxV2{dep}, EffectiveNonce: 42},
+			} {
+				assertDepositRoundTrips(t, inner)
+			}
+		}
+	}
+}
+
+func TestDepositTxV2LargeMintJSON(t *testing.T) {
+	mint := new(big.Int).Lsh(big.NewInt(1), 255)
+	inner := newTestDepositTxV2(WithMint(mint))
+	enc, err := json.Marshal(NewTx(inner))
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(enc, &raw); err != nil {
+		t.Fatalf("unmarshal of raw fields failed: %v", err)
+	}
+	if want := `"0x8` + strings.Repeat("0", 63) + `"`; string(raw["mint"]) != want {
+		t.Errorf("mint encoding mismatch: have %s, want %s", raw["mint"], want)
+	}
+	var dec Transaction
+	if err := dec.UnmarshalJSON(enc); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if dec.Mint().Cmp(mint) != 0 {
+		t.Errorf("mint mismatch after round trip: have %v, want %v", dec.Mint(), mint)
+	}
+	assertDepositRoundTrips(t, inner)
+}
+
+func TestDepositTxProtected(t *testing.T) {
+	dep := newTestDepositTxV2().DepositTx
+	for _, inner := range []TxData{
+		&dep,
+		&depositTxWithNonce{DepositTx: dep, EffectiveNonce: 7},
+		&DepositTxV2{dep},
+		&depositTxV2WithNonce{DepositTxV2: DepositTxV2{dep}, EffectiveNonce: 42},
+	} {
+		if (&Transaction{inner: inner}).Protected() {
+			t.Errorf("%T: deposit reported as replay-protected", inner)
+		}
+	}
+	if !NewTx(&DynamicFeeTx{}).Protected() {
+		t.Error("typed transaction should be replay-protected")
+	}
+}
+
+func TestDepositTxV2Data(t *testing.T) {
+	data := []byte("test data")
+	tx := NewTx(newTestDepositTxV2(WithData(data)))
+	wrapped, err := tx.WithEffectiveNonce(7)
+	if err != nil {
+		t.Fatalf("failed to wrap deposit: %v", err)
+	}
+	hash := tx.Hash()
+
+	// The constructors copy the input, so callers may reuse their buffers
+	data[0] = 'X'
+	for name, tx := range map[string]*Transaction{"bare": tx, "wrapped": wrapped} {
+		if !bytes.Equal(tx.Data(), []byte("test data")) {
+			t.Errorf("%s: data mismatch: have %q", name, tx.Data())
+		}
+		if tx.Hash() != hash {
+			t.Errorf("%s: hash mismatch: have %v, want %v", name, tx.Hash(), hash)
+		}
+	}
+	// Data itself is shared with the transaction and documented as read-only
+	if &tx.Data()[0] != &tx.Data()[0] {
+		t.Error("Data unexpectedly returned a copy")
+	}
+	if &tx.Data()[0] == &wrapped.Data()[0] {
+		t.Error("wrapped deposit shares data with the original")
+	}
+}
+
+func TestDepositInner(t *testing.T) {
+	dep := newTestDepositTxV2(WithSystem()).DepositTx
+	for _, inner := range []TxData{
+		&dep,
+		&depositTxWithNonce{DepositTx: dep, EffectiveNonce: 7},
+		&DepositTxV2{dep},
+		&depositTxV2WithNonce{DepositTxV2: DepositTxV2{dep}, EffectiveNonce: 42},
+	} {
+		tx := &Transaction{inner: inner}
+		sourceHash, from, mint, value, gas, isSystem, ok := tx.DepositInner()
+		if !ok {
+			t.Fatalf("%T: deposit not recognized", inner)
+		}
+		if sourceHash != dep.SourceHash || from != dep.From || gas != dep.Gas || isSystem != dep.IsSystemTransaction {
+			t.Errorf("%T: field mismatch: have (%v, %v, %d, %v)", inner, sourceHash, from, gas, isSystem)
+		}
+		if mint.Cmp(dep.Mint) != 0 || value.Cmp(dep.Value) != 0 {
+			t.Errorf("%T: amount mismatch: have mint %v, value %v", inner, mint, value)
+		}
+		// The amounts are copies
+		mint.SetUint64(1)
+		value.SetUint64(1)
+		if tx.Mint().Cmp(dep.Mint) != 0 || tx.Value().Cmp(dep.Value) != 0 {
+			t.Errorf("%T: transaction modified through returned amounts", inner)
+		}
+	}
+	if _, _, mint, _, _, _, ok := newTestDepositTxV2Tx(WithMint(nil)).DepositInner(); !ok || mint != nil {
+		t.Errorf("deposit without mint: have mint %v, ok %v", mint, ok)
+	}
+	if _, _, _, _, _, _, ok := NewTx(&LegacyTx{}).DepositInner(); ok {
+		t.Error("non-deposit reported as deposit")
+	}
+}
+
+func TestDepositTxV2String(t *testing.T) {
+	dep := newTestDepositTxV2(WithSystem())
+	want := "DepositTxV2{sourceHash: " + dep.SourceHash.Hex() + ", from: " + testDepositAddr.Hex() +
+		", to: " + testDepositAddr.Hex() + ", mint: 1000, value: 2000, gas: 50000, system: true, data: 9 bytes}"
+	if have := dep.String(); have != want {
+		t.Errorf("string mismatch:\nhave %s\nwant %s", have, want)
+	}
+	creation := newTestDepositTxV2(WithNilTo(), WithMint(nil))
+	if have := creation.String(); !strings.Contains(have, "to: nil") || !strings.Contains(have, "mint: nil") {
+		t.Errorf("unset fields not reported: %s", have)
+	}
+	wrapped := newTestDepositTxV2Tx(WithSystem(), WithNonce(7)).inner.(fmt.Stringer)
+	if have := wrapped.String(); have != strings.TrimSuffix(want, "}")+", nonce: 7}" {
+		t.Errorf("wrapped string mismatch: %s", have)
+	}
+}
+
+func TestIsDepositWithNonce(t *testing.T) {
+	dep := newTestDepositTxV2()
+	for _, tc := range []struct {
+		inner TxData
+		want  bool
+	}{
+		{&LegacyTx{}, false},
+		{&dep.DepositTx, false},
+		{&depositTxWithNonce{DepositTx: dep.DepositTx, EffectiveNonce: 7}, false},
+		{dep, false},
+		{&depositTxV2WithNonce{DepositTxV2: *dep, EffectiveNonce: 42}, true},
+	} {
+		if have := (&Transaction{inner: tc.inner}).IsDepositWithNonce(); have != tc.want {
+			t.Errorf("%T: have %v, want %v", tc.inner, have, tc.want)
+		}
+	}
+}
+
+func TestDepositChainId(t *testing.T) {
+	dep := newTestDepositTxV2()
+	for _, inner := range []TxData{
+		&dep.DepositTx,
+		&depositTxWithNonce{DepositTx: dep.DepositTx, EffectiveNonce: 7},
+		dep,
+		&depositTxV2WithNonce{DepositTxV2: *dep, EffectiveNonce: 42},
+	} {
+		id := (&Transaction{inner: inner}).ChainId()
+		if id == nil || id.Sign() != 0 {
+			t.Errorf("%T: chain id mismatch: have %v, want 0", inner, id)
+		}
+	}
+}
+
+func TestDepositFields(t *testing.T) {
+	var (
+		dep     = newTestDepositTxV2(WithSystem())
+		nonceV1 = uint64(7)
+		nonceV2 = uint64(42)
+	)
+	for _, tc := range []struct {
+		inner TxData
+		nonce *uint64
+	}{
+		{&dep.DepositTx, nil},
+		{&depositTxWithNonce{DepositTx: dep.DepositTx, EffectiveNonce: nonceV1}, &nonceV1},
+		{dep, nil},
+		{&depositTxV2WithNonce{DepositTxV2: *dep, EffectiveNonce: nonceV2}, &nonceV2},
+	} {
+		tx := &Transaction{inner: tc.inner}
+		fields := tx.DepositFields()
+		if fields == nil {
+			t.Fatalf("%T: deposit not recognized", tc.inner)
+		}
+		if fields.SourceHash != dep.SourceHash || fields.From != dep.From || fields.Gas != dep.Gas || !fields.IsSystemTx {
+			t.Errorf("%T: field mismatch: %+v", tc.inner, fields)
+		}
+		if fields.To == nil || *fields.To != *dep.To || fields.Mint.Cmp(dep.Mint) != 0 || fields.Value.Cmp(dep.Value) != 0 {
+			t.Errorf("%T: field mismatch: to %v, mint %v, value %v", tc.inner, fields.To, fields.Mint, fields.Value)
+		}
+		if (fields.Nonce == nil) != (tc.nonce == nil) || (tc.nonce != nil && *fields.Nonce != *tc.nonce) {
+			t.Errorf("%T: nonce mismatch: have %v, want %v", tc.inner, fields.Nonce, tc.nonce)
+		}
+		// The fields are copies of the transaction's
+		if fields.Nonce != nil {
+			*fields.Nonce++
+			if *tx.EffectiveNonce() != *tc.nonce {
+				t.Errorf("%T: transaction modified through returned nonce", tc.inner)
+			}
+		}
+	}
+	if fields := NewTx(&LegacyTx{}).DepositFields(); fields != nil {
+		t.Errorf("non-deposit returned fields: %+v", fields)
+	}
+}
+
+func TestDepositTxIsSystemTx(t *testing.T) {
+	for _, system := range []bool{true, false} {
+		dep := DepositTx{SourceHash: common.HexToHash("0xdeadbeef"), Value: big.NewInt(0), IsSystemTransaction: system}
+		for _, inner := range []TxData{
+			&dep,
+			&DepositTxV2{dep},
+			&depositTxV2WithNonce{DepositTxV2: DepositTxV2{dep}, EffectiveNonce: 42},
+		} {
+			if have := NewTx(inner).IsSystemTx(); have != system {
+				t.Errorf("%T: IsSystemTx mismatch: have %v, want %v", inner, have, system)
+			}
+		}
+	}
+	if NewTx(&DynamicFeeTx{}).IsSystemTx() {
+		t.Error("non-deposit transaction should never be a system transaction")
+	}
+}
+
+// newBatchTestDeposits creates n distinct fixture deposits, differing in source
+// hash, mint and data.
+func newBatchTestDeposits(n int) []*DepositTxV2 {
+	txs := make([]*DepositTxV2, n)
+	for i := range txs {
+		txs[i] = newTestDepositTxV2(
+			WithSourceHash(common.BigToHash(big.NewInt(int64(i+1)))),
+			WithMint(big.NewInt(int64(i))),
+			WithData(bytes.Repeat([]byte{byte(i)}, i%64)),
+		)
+	}
+	return txs
+}
+
+func TestEncodeDepositTxV2Batch(t *testing.T) {
+	txs := newBatchTestDeposits(100)
+
+	batch, err := EncodeDepositTxV2Batch(txs)
+	if err != nil {
+		t.Fatalf("failed to batch encode: %v", err)
+	}
+	if len(batch) != len(txs) {
+		t.Fatalf("batch length mismatch: have %d, want %d", len(batch), len(txs))
+	}
+	for i, tx := range txs {
+		var buf bytes.Buffer
+		buf.WriteByte(DepositTxV2Type)
+		if err := tx.encode(&buf); err != nil {
+			t.Fatalf("failed to encode deposit %d: %v", i, err)
+		}
+		if !bytes.Equal(batch[i], buf.Bytes()) {
+			t.Errorf("deposit %d: encoding mismatch:\nhave %x\nwant %x", i, batch[i], buf.Bytes())
+		}
+		want, _ := NewTx(tx).MarshalBinary()
+		if !bytes.Equal(batch[i], want) {
+			t.Errorf("deposit %d: mismatch with MarshalBinary:\nhave %x\nwant %x", i, batch[i], want)
+		}
+	}
+}
+
+func BenchmarkEncodeDepositTxV2(b *testing.B) {
+	txs := newBatchTestDeposits(100)
+
+	b.Run("individual", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, tx := range txs {
+				buf := new(bytes.Buffer)
+				buf.WriteByte(DepositTxV2Type)
+				tx.encode(buf)
+			}
+		}
+	})
+	b.Run("batch", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			EncodeDepositTxV2Batch(txs)
+		}
+	})
+}
+
+func TestDepositTxV2EncodeInto(t *testing.T) {
+	buf := encodeBufferPool.Get().(*bytes.Buffer)
+	defer encodeBufferPool.Put(buf)
+
+	// Leave some garbage in the buffer to ensure it gets reset
+	buf.WriteString("garbage")
+	for i, tx := range newBatchTestDeposits(10) {
+		var want bytes.Buffer
+		if err := tx.encode(&want); err != nil {
+			t.Fatalf("deposit %d: failed to encode: %v", i, err)
+		}
+		if err := tx.encodeInto(buf); err != nil {
+			t.Fatalf("deposit %d: failed to encode into buffer: %v", i, err)
+		}
+		if !bytes.Equal(buf.Bytes(), want.Bytes()) {
+			t.Errorf("deposit %d: encoding mismatch:\nhave %x\nwant %x", i, buf.Bytes(), want.Bytes())
+		}
+	}
+}
+
+func BenchmarkDepositTxV2EncodeInto(b *testing.B) {
+	txs := newBatchTestDeposits(10_000)
+
+	b.Run("fresh", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			for _, tx := range txs {
+				tx.encode(new(bytes.Buffer))
+			}
+		}
+	})
+	b.Run("reuse", func(b *testing.B) {
+		b.ReportAllocs()
+		buf := encodeBufferPool.Get().(*bytes.Buffer)
+		defer encodeBufferPool.Put(buf)
+		for i := 0; i < b.N; i++ {
+			for _, tx := range txs {
+				tx.encodeInto(buf)
+			}
+		}
+	})
+}
+
+func TestDepositTxV2NegativeAmounts(t *testing.T) {
+	addr := testDepositAddr
+	base := func() *DepositTxV2 {
+		return &DepositTxV2{DepositTx{
+			SourceHash: common.HexToHash("0x1234"),
+			From:       addr,
+			To:         &addr,
+			Mint:       big.NewInt(10),
+			Value:      big.NewInt(20),
+			Gas:        21000,
+			Data:       []byte{},
+		}}
+	}
+
+	// Hand-built payloads with negative amounts must be rejected.
+	tx := base()
+	tx.Value = big.NewInt(-1)
+	if err := tx.validateAmounts(); !errors.Is(err, ErrDepositNegativeValue) {
+		t.Errorf("negative value: have %v, want %v", err, ErrDepositNegativeValue)
+	}
+	tx = base()
+	tx.Mint = big.NewInt(-1)
+	if err := tx.validateAmounts(); !errors.Is(err, ErrDepositNegativeMint) {
+		t.Errorf("negative mint: have %v, want %v", err, ErrDepositNegativeMint)
+	}
+	tx = base()
+	tx.Mint = nil
+	if err := tx.validateAmounts(); err != nil {
+		t.Errorf("nil mint: unexpected error %v", err)
+	}
+
+	// Negative hex quantities in JSON must not decode.
+	enc, err := json.Marshal(NewTx(base()))
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	for _, field := range []string{"value", "mint"} {
+		var fields map[string]interface{}
+		if err := json.Unmarshal(enc, &fields); err != nil {
+			t.Fatalf("failed to unmarshal fields: %v", err)
+		}
+		fields[field] = "-0x1"
+		crafted, _ := json.Marshal(fields)
+		var dec Transaction
+		if err := dec.UnmarshalJSON(crafted); err == nil {
+			t.Errorf("negative %s: expected JSON decode error", field)
+		}
+	}
+
+	// RLP cannot carry a negative integer: encoding refuses, and any crafted
+	// payload decodes to non-negative amounts that pass validation.
+	tx = base()
+	tx.Value = big.NewInt(-1)
+	if _, err := rlp.EncodeToBytes(tx); err == nil {
+		t.Error("expected RLP encoding of negative value to fail")
+	}
+	payload, err := rlp.EncodeToBytes(base())
+	if err != nil {
+		t.Fatalf("failed to encode: %v", err)
+	}
+	var decoded DepositTxV2
+	if err := decoded.decode(payload); err != nil {
+		t.Fatalf("failed to decode valid payload: %v", err)
+	}
+	raw := append([]byte{DepositTxV2Type}, payload...)
+	if err := new(Transaction).UnmarshalBinary(raw); err != nil {
+		t.Fatalf("failed to unmarshal valid binary: %v", err)
+	}
+}
+
+func TestDepositRawSignatureValues(t *testing.T) {
+	dep := DepositTx{
+		SourceHash: common.HexToHash("0x1234"),
+		Value:      big.NewInt(1),
+		Gas:        21000,
+	}
+	for _, inner := range []TxData{
+		&dep,
+		&DepositTxV2{DepositTx: dep},
+		&depositTxWithNonce{DepositTx: dep, EffectiveNonce: 7},
+		&depositTxV2WithNonce{DepositTxV2: DepositTxV2{DepositTx: dep}, EffectiveNonce: 7},
+	} {
+		tx := NewTx(inner)
+		v, r, s := tx.RawSignatureValues()
+		if v == nil || r == nil || s == nil {
+			t.Fatalf("%T: nil signature value", inner)
+		}
+		if v.Sign() != 0 || r.Sign() != 0 || s.Sign() != 0 {
+			t.Errorf("%T: have v=%v r=%v s=%v, want zeros", inner, v, r, s)
+		}
+		// The returned values must not alias shared state.
+		v.SetUint64(1)
+		if v2, _, _ := tx.RawSignatureValues(); v2.Sign() != 0 || common.Big0.Sign() != 0 {
+			t.Errorf("%T: mutating returned v leaked into the transaction", inner)
+		}
+	}
+}
+
+func TestDepositTxV2UnmarshalJSONStrict(t *testing.T) {
+	enc, err := json.Marshal(newTestDepositTxV2Tx())
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	modify := func(f func(map[string]json.RawMessage)) []byte {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(enc, &fields); err != nil {
+			t.Fatalf("failed to unmarshal fields: %v", err)
+		}
+		f(fields)
+		out, err := json.Marshal(fields)
+		if err != nil {
+			t.Fatalf("failed to marshal fields: %v", err)
+		}
+		return out
+	}
+
+	// The unmodified encoding is accepted by both decoders.
+	if err := new(Transaction).UnmarshalJSONStrict(enc); err != nil {
+		t.Errorf("strict: unexpected error %v", err)
+	}
+
+	// Unknown fields are rejected by the strict decoder only.
+	extra := modify(func(m map[string]json.RawMessage) { m["unknownField"] = json.RawMessage(`"0x1"`) })
+	if err := new(Transaction).UnmarshalJSONStrict(extra); err == nil {
+		t.Error("strict: expected error for unknown field")
+	}
+	if err := new(Transaction).UnmarshalJSON(extra); err != nil {
+		t.Errorf("lenient: unexpected error for unknown field: %v", err)
+	}
+
+	// Missing required fields are rejected.
+	for _, field := range []string{"sourceHash", "from"} {
+		missing := modify(func(m map[string]json.RawMessage) { delete(m, field) })
+		if err := new(Transaction).UnmarshalJSONStrict(missing); err == nil {
+			t.Errorf("strict: expected error for missing %s", field)
+		}
+	}
+
+	// Signature and fee fields are outside of the deposit encoding.
+	for _, field := range []string{"v", "maxFeePerGas"} {
+		set := modify(func(m map[string]json.RawMessage) { m[field] = json.RawMessage(`"0x0"`) })
+		if err := new(Transaction).UnmarshalJSONStrict(set); err == nil {
+			t.Errorf("strict: expected error for %s", field)
+		}
+	}
+}
+
+func TestDepositTxV2MarshalJSONOmitsSignature(t *testing.T) {
+	dep := *newTestDepositTxV2()
+	excluded := []string{"v", "r", "s", "yParity", "gasPrice", "maxFeePerGas", "maxPriorityFeePerGas", "chainId"}
+
+	for _, tt := range []struct {
+		inner TxData
+		nonce bool
+	}{
+		{&dep, false},
+		{&depositTxV2WithNonce{DepositTxV2: dep, EffectiveNonce: 0x42}, true},
+	} {
+		tx := &Transaction{inner: tt.inner}
+		enc, err := json.Marshal(tx)
+		if err != nil {
+			t.Fatalf("%T: failed to marshal: %v", tt.inner, err)
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(enc, &fields); err != nil {
+			t.Fatalf("%T: failed to unmarshal fields: %v", tt.inner, err)
+		}
+		for _, key := range excluded {
+			if _, ok := fields[key]; ok {
+				t.Errorf("%T: unexpected field %q in %s", tt.inner, key, enc)
+			}
+		}
+		for _, key := range []string{"type", "sourceHash", "from", "to", "mint", "value", "gas", "input", "isSystemTx", "hash"} {
+			if _, ok := fields[key]; !ok {
+				t.Errorf("%T: missing field %q in %s", tt.inner, key, enc)
+			}
+		}
+		if _, ok := fields["nonce"]; ok != tt.nonce {
+			t.Errorf("%T: nonce presence mismatch: have %v, want %v", tt.inner, ok, tt.nonce)
+		}
+
+		// The encoding must round-trip, including the effective nonce.
+		var dec Transaction
+		if err := json.Unmarshal(enc, &dec); err != nil {
+			t.Fatalf("%T: failed to decode: %v", tt.inner, err)
+		}
+		if dec.Hash() != tx.Hash() {
+			t.Errorf("%T: hash mismatch after round trip", tt.inner)
+		}
+		if (dec.EffectiveNonce() != nil) != tt.nonce {
+			t.Errorf("%T: effective nonce lost in round trip", tt.inner)
+		}
+	}
+}
+
+func TestDepositTxV2WithEffectiveNonce(t *testing.T) {
+	bare := NewTx(newTestDepositTxV2())
+
+	wrapped, err := bare.WithEffectiveNonce(7)
+	if err != nil {
+		t.Fatalf("failed to wrap deposit: %v", err)
+	}
+	if _, ok := wrapped.inner.(*depositTxV2WithNonce); !ok {
+		t.Fatalf("wrapped inner has type %T, want *depositTxV2WithNonce", wrapped.inner)
+	}
+	if nonce := wrapped.EffectiveNonce(); nonce == nil || *nonce != 7 {
+		t.Errorf("effective nonce mismatch: have %v, want 7", nonce)
+	}
+	if wrapped.Hash() != bare.Hash() {
+		t.Error("wrapping changed the transaction hash")
+	}
+	if bare.EffectiveNonce() != nil {
+		t.Error("wrapping modified the receiver")
+	}
+
+	// Wrapping an already wrapped deposit must be refused.
+	if _, err := wrapped.WithEffectiveNonce(8); !errors.Is(err, ErrDepositNonceWrapped) {
+		t.Errorf("double wrap: have %v, want %v", err, ErrDepositNonceWrapped)
+	}
+	if _, err := wrapDepositNonce(wrapped.inner, 8); !errors.Is(err, ErrDepositNonceWrapped) {
+		t.Errorf("double wrap of inner: have %v, want %v", err, ErrDepositNonceWrapped)
+	}
+	// Non-deposits cannot carry an effective nonce.
+	legacy := NewTransaction(0, testDepositAddr, big.NewInt(0), 21000, big.NewInt(1), nil)
+	if _, err := legacy.WithEffectiveNonce(1); !errors.Is(err, ErrInvalidTxType) {
+		t.Errorf("legacy: have %v, want %v", err, ErrInvalidTxType)
+	}
+}
+
+func TestDepositTxV2Cost(t *testing.T) {
+	value := big.NewInt(2000)
+	for _, mint := range []*big.Int{nil, big.NewInt(0), big.NewInt(1000)} {
+		tx := NewTx(newTestDepositTxV2(WithMint(mint), WithValue(value)))
+		if cost := tx.Cost(); cost.Cmp(value) != 0 {
+			t.Errorf("mint %v: cost mismatch: have %v, want %v", mint, cost, value)
+		}
+	}
+}
+
+func TestDepositTxV2FeeGetters(t *testing.T) {
+	inner := *newTestDepositTxV2()
+	txs := map[string]*Transaction{
+		"bare":    NewTx(&inner),
+		"wrapped": {inner: &depositTxV2WithNonce{DepositTxV2: inner, EffectiveNonce: 7}},
+	}
+	for name, tx := range txs {
+		for getter, fee := range map[string]*big.Int{
+			"GasFeeCap": tx.GasFeeCap(),
+			"GasTipCap": tx.GasTipCap(),
+			"GasPrice":  tx.GasPrice(),
+		} {
+			if fee == nil || fee.Sign() != 0 {
+				t.Errorf("%s: %s mismatch: have %v, want 0", name, getter, fee)
+			}
+		}
+	}
+	// Comparators must treat both forms identically
+	if cmp := txs["bare"].GasFeeCapCmp(txs["wrapped"]); cmp != 0 {
+		t.Errorf("fee cap comparison mismatch: have %d, want 0", cmp)
+	}
+	if cmp := txs["bare"].GasTipCapCmp(txs["wrapped"]); cmp != 0 {
+		t.Errorf("tip cap comparison mismatch: have %d, want 0", cmp)
+	}
+}
+
+func TestDepositTxV2NonCanonicalAmounts(t *testing.T) {
+	// payload encodes the deposit fields with the given raw Mint and Value.
+	payload := func(mint, value []byte) []byte {
+		enc, err := rlp.EncodeToBytes([]interface{}{
+			common.HexToHash("0xdeadbeef"),
+			common.HexToAddress("0x1234"),
+			[]byte{}, // contract creation
+			rlp.RawValue(mint),
+			rlp.RawValue(value),
+			uint64(50000),
+			false,
+			[]byte{},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return enc
+	}
+	canonical := []byte{0x82, 0x01, 0x00}         // 256
+	leadingZero := []byte{0x83, 0x00, 0x01, 0x00} // 256 with a leading zero byte
+
+	var tx DepositTxV2
+	if err := tx.decode(payload(canonical, canonical)); err != nil {
+		t.Fatalf("canonical encoding rejected: %v", err)
+	}
+	if tx.Mint.Int64() != 256 || tx.Value.Int64() != 256 {
+		t.Fatalf("decoded amounts mismatch: mint %v, value %v", tx.Mint, tx.Value)
+	}
+	for name, enc := range map[string][]byte{
+		"mint":  payload(leadingZero, canonical),
+		"value": payload(canonical, leadingZero),
+	} {
+		if err := new(DepositTxV2).decode(enc); err == nil || !strings.Contains(err.Error(), "non-canonical") {
+			t.Errorf("%s: non-canonical encoding not rejected: %v", name, err)
+		}
+	}
+}
+
+func TestDepositTxV2NilToEncoding(t *testing.T) {
+	encode := func(to *common.Address) []byte {
+		var buf bytes.Buffer
+		tx := &DepositTxV2{DepositTx{SourceHash: common.HexToHash("0xdeadbeef"), To: to, Value: big.NewInt(0), Gas: 50000}}
+		if err := tx.encode(&buf); err != nil {
+			t.Fatalf("failed to encode deposit: %v", err)
+		}
+		return buf.Bytes()
+	}
+	zero := common.Address{}
+	// The To field follows the 33 byte source hash and 21 byte sender, after the
+	// list header
+	const toOffset = 2 + 33 + 21
+	nilTo, zeroTo := encode(nil), encode(&zero)
+	if nilTo[toOffset] != 0x80 {
+		t.Errorf("nil To not encoded as empty string: have %#x", nilTo[toOffset])
+	}
+	if want := append([]byte{0x94}, zero[:]...); !bytes.Equal(zeroTo[toOffset:toOffset+21], want) {
+		t.Errorf("zero To encoding mismatch: have %x, want %x", zeroTo[toOffset:toOffset+21], want)
+	}
+	if len(zeroTo)-len(nilTo) != common.AddressLength {
+		t.Errorf("encoding length difference mismatch: have %d, want %d", len(zeroTo)-len(nilTo), common.AddressLength)
+	}
+	for name, tc := range map[string]struct {
+		enc []byte
+		to  *common.Address
+	}{"nil": {nilTo, nil}, "zero": {zeroTo, &zero}} {
+		var dec DepositTxV2
+		if err := dec.decode(tc.enc); err != nil {
+			t.Fatalf("%s: failed to decode deposit: %v", name, err)
+		}
+		if !equalAddressPtr(dec.To, tc.to) {
+			t.Errorf("%s: decoded To mismatch: have %v, want %v", name, dec.To, tc.to)
+		}
+	}
+}
+
+func TestDepositTxV2BlockBodyRLP(t *testing.T) {
+	deposit := newTestDepositTxV2Tx(WithNonce(7))
+	legacy := NewTransaction(3, testDepositAddr, big.NewInt(10), 21000, big.NewInt(1), nil)
+	body := &Body{Transactions: []*Transaction{legacy, deposit}}
+
+	enc, err := rlp.EncodeToBytes(body)
+	if err != nil {
+		t.Fatalf("failed to encode body: %v", err)
+	}
+	var dec Body
+	if err := rlp.DecodeBytes(enc, &dec); err != nil {
+		t.Fatalf("failed to decode body: %v", err)
+	}
+	if len(dec.Transactions) != 2 {
+		t.Fatalf("transaction count mismatch: have %d, want 2", len(dec.Transactions))
+	}
+	for i, want := range body.Transactions {
+		if have := dec.Transactions[i]; have.Hash() != want.Hash() {
+			t.Errorf("tx %d: hash mismatch: have %v, want %v", i, have.Hash(), want.Hash())
+		}
+	}
+	if _, ok := dec.Transactions[0].inner.(*LegacyTx); !ok {
+		t.Errorf("tx 0: inner has type %T, want *LegacyTx", dec.Transactions[0].inner)
+	}
+	// The effective nonce is not part of the consensus encoding, so the deposit
+	// decodes as a bare DepositTxV2.
+	if _, ok := dec.Transactions[1].inner.(*DepositTxV2); !ok {
+		t.Errorf("tx 1: inner has type %T, want *DepositTxV2", dec.Transactions[1].inner)
+	}
+	if dec.Transactions[1].EffectiveNonce() != nil {
+		t.Error("tx 1: decoded deposit carries an effective nonce")
+	}
+}
+
+func TestDepositTxV2To(t *testing.T) {
+	// Creation deposits have no recipient
+	creation := newTestDepositTxV2Tx(WithNilTo())
+	if to := creation.To(); to != nil {
+		t.Errorf("creation deposit: have recipient %v, want nil", to)
+	}
+
+	// Calls return a copy that does not alias the internal field
+	inner := newTestDepositTxV2()
+	call := &Transaction{inner: inner}
+	to := call.To()
+	if to == nil || *to != testDepositAddr {
+		t.Fatalf("call deposit: recipient mismatch: have %v, want %v", to, testDepositAddr)
+	}
+	if to == inner.To {
+		t.Fatal("call deposit: returned recipient aliases the internal field")
+	}
+	to[0] = 0xff
+	if *inner.To != testDepositAddr || *call.To() != testDepositAddr {
+		t.Error("call deposit: mutating the returned recipient changed the transaction")
+	}
+}
+
+func TestDepositTxV2MarshalBinary(t *testing.T) {
+	for i, dep := range newBatchTestDeposits(4) {
+		enc, err := dep.MarshalBinary()
+		if err != nil {
+			t.Fatalf("deposit %d: failed to marshal: %v", i, err)
+		}
+		if enc[0] != DepositTxV2Type {
+			t.Errorf("deposit %d: envelope type mismatch: have %#x, want %#x", i, enc[0], DepositTxV2Type)
+		}
+		// The encoding must match the one of a transaction wrapping the deposit
+		want, err := NewTx(dep).MarshalBinary()
+		if err != nil {
+			t.Fatalf("deposit %d: failed to marshal transaction: %v", i, err)
+		}
+		if !bytes.Equal(enc, want) {
+			t.Errorf("deposit %d: encoding mismatch:\nhave %x\nwant %x", i, enc, want)
+		}
+		var dec DepositTxV2
+		if err := dec.UnmarshalBinary(enc); err != nil {
+			t.Fatalf("deposit %d: failed to unmarshal: %v", i, err)
+		}
+		if NewTx(&dec).Hash() != NewTx(dep).Hash() {
+			t.Errorf("deposit %d: hash mismatch after round trip", i)
+		}
+	}
+
+	// Foreign and truncated envelopes are rejected
+	enc, _ := newTestDepositTxV2().MarshalBinary()
+	enc[0] = DepositTxType
+	if err := new(DepositTxV2).UnmarshalBinary(enc); !errors.Is(err, ErrInvalidTxType) {
+		t.Errorf("foreign type: have %v, want %v", err, ErrInvalidTxType)
+	}
+	if err := new(DepositTxV2).UnmarshalBinary([]byte{DepositTxV2Type}); !errors.Is(err, errShortTypedTx) {
+		t.Errorf("short envelope: have %v, want %v", err, errShortTypedTx)
+	}
+}
+
+func TestDecodeDepositTxV2Hex(t *testing.T) {
+	dep := newTestDepositTxV2()
+	enc, err := dep.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	raw := hex.EncodeToString(enc)
+
+	// Both prefixed and bare hex decode to the same deposit
+	for _, s := range []string{"0x" + raw, raw} {
+		dec, err := DecodeDepositTxV2Hex(s)
+		if err != nil {
+			t.Fatalf("input %q: failed to decode: %v", s, err)
+		}
+		if !dec.Equal(dep) {
+			t.Errorf("input %q: deposit mismatch after decoding", s)
+		}
+	}
+	// Malformed hex is reported as a hex error
+	if _, err := DecodeDepositTxV2Hex("0x" + raw[1:]); !errors.Is(err, hex.ErrLength) {
+		t.Errorf("odd-length hex: have %v, want %v", err, hex.ErrLength)
+	}
+	// Foreign envelopes are reported as an encoding error
+	foreign := "0x" + hex.EncodeToString([]byte{DepositTxType}) + raw[2:]
+	if _, err := DecodeDepositTxV2Hex(foreign); !errors.Is(err, ErrInvalidTxType) {
+		t.Errorf("foreign type: have %v, want %v", err, ErrInvalidTxType)
+	}
+}
+
+// TestDepositTxV2LocalTime checks that the local arrival time attached through
+// SetTime is bookkeeping only: it affects neither the hash nor the encoding, and
+// survives nonce wrapping and unwrapping.
+func TestDepositTxV2LocalTime(t *testing.T) {
+	dep := newTestDepositTxV2()
+	plain := NewTx(dep)
+	wantEnc, err := plain.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	arrival := time.Unix(1700000000, 0)
+
+	tx := NewTx(dep)
+	tx.SetTime(arrival)
+	if !tx.Time().Equal(arrival) {
+		t.Errorf("time mismatch: have %v, want %v", tx.Time(), arrival)
+	}
+	if tx.Hash() != plain.Hash() {
+		t.Errorf("hash mismatch: have %v, want %v", tx.Hash(), plain.Hash())
+	}
+	enc, err := tx.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	if !bytes.Equal(enc, wantEnc) {
+		t.Errorf("encoding mismatch:\nhave %x\nwant %x", enc, wantEnc)
+	}
+	wrapped, err := tx.WithEffectiveNonce(7)
+	if err != nil {
+		t.Fatalf("failed to wrap deposit: %v", err)
+	}
+	if !wrapped.Time().Equal(arrival) || !wrapped.WithoutEffectiveNonce().Time().Equal(arrival) {
+		t.Errorf("time lost across nonce wrapping: wrapped %v, unwrapped %v", wrapped.Time(), wrapped.WithoutEffectiveNonce().Time())
+	}
+}
+
+// TestDepositTxV2WithNonceSinglePrefix checks that the envelope of a nonce-wrapped
+// deposit carries the type byte exactly once, both standalone and nested in RLP
+// as in block bodies, and that it decodes back to the same deposit.
+func TestDepositTxV2WithNonceSinglePrefix(t *testing.T) {
+	bare := newTestDepositTxV2Tx()
+	wrapped := newTestDepositTxV2Tx(WithNonce(7))
+
+	enc, err := wrapped.MarshalBinary()
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	// The type byte is directly followed by the payload list header
+	if enc[0] != DepositTxV2Type || enc[1] < 0xc0 {
+		t.Fatalf("envelope prefix mismatch: have %x", enc[:2])
+	}
+	want, _ := bare.MarshalBinary()
+	if !bytes.Equal(enc, want) {
+		t.Errorf("wrapped encoding differs from bare one:\nhave %x\nwant %x", enc, want)
+	}
+	var dec Transaction
+	if err := dec.UnmarshalBinary(enc); err != nil {
+		t.Fatalf("failed to unmarshal: %v", err)
+	}
+	if dec.Type() != DepositTxV2Type || dec.Hash() != wrapped.Hash() {
+		t.Errorf("decoded mismatch: type %#x, hash %v, want %v", dec.Type(), dec.Hash(), wrapped.Hash())
+	}
+
+	// In RLP the envelope is wrapped in a byte string, with no further type byte
+	rlpEnc, err := rlp.EncodeToBytes(wrapped)
+	if err != nil {
+		t.Fatalf("failed to RLP encode: %v", err)
+	}
+	content, _, err := rlp.SplitString(rlpEnc)
+	if err != nil {
+		t.Fatalf("RLP encoding is not a byte string: %v", err)
+	}
+	if !bytes.Equal(content, enc) {
+		t.Errorf("RLP content mismatch:\nhave %x\nwant %x", content, enc)
+	}
+}
+
+func TestDepositTxV2WithoutEffectiveNonce(t *testing.T) {
+	dep := newTestDepositTxV2()
+	bare := NewTx(dep)
+	wrapped, err := bare.WithEffectiveNonce(7)
+	if err != nil {
+		t.Fatalf("failed to wrap deposit: %v", err)
+	}
+
+	unwrapped := wrapped.WithoutEffectiveNonce()
+	if _, ok := unwrapped.inner.(*DepositTxV2); !ok {
+		t.Fatalf("unwrapped inner has type %T, want *DepositTxV2", unwrapped.inner)
+	}
+	if unwrapped.EffectiveNonce() != nil {
+		t.Error("unwrapped deposit still carries an effective nonce")
+	}
+	if unwrapped.Hash() != bare.Hash() {
+		t.Errorf("hash mismatch: have %v, want %v", unwrapped.Hash(), bare.Hash())
+	}
+	if wrapped.EffectiveNonce() == nil {
+		t.Error("unwrapping modified the receiver")
+	}
+
+	// The nonce must disappear from the JSON encoding
+	for _, tt := range []struct {
+		tx    *Transaction
+		nonce bool
+	}{{wrapped, true}, {unwrapped, false}} {
+		enc, err := json.Marshal(tt.tx)
+		if err != nil {
+			t.Fatalf("failed to marshal: %v", err)
+		}
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(enc, &fields); err != nil {
+			t.Fatalf("failed to unmarshal fields: %v", err)
+		}
+		if _, ok := fields["nonce"]; ok != tt.nonce {
+			t.Errorf("nonce presence mismatch: have %v, want %v: %s", ok, tt.nonce, enc)
+		}
+	}
+
+	// Transactions without an effective nonce are returned as is
+	if bare.WithoutEffectiveNonce() != bare {
+		t.Error("bare deposit was not returned unchanged")
+	}
+}
+
+func TestDepositTxV2Equal(t *testing.T) {
+	// Use a zero, rather than nil, mint
+	base := func() *DepositTxV2 { return newTestDepositTxV2(WithMint(new(big.Int))) }
+	if tx := base(); !tx.Equal(base()) {
+		t.Error("identical deposits are not equal")
+	}
+	if tx := base(); !tx.Equal(tx.copy().(*DepositTxV2)) {
+		t.Error("deposit is not equal to its copy")
+	}
+
+	for _, tc := range []struct {
+		name   string
+		modify func(*DepositTxV2)
+	}{
+		{"nil vs zero mint", func(tx *DepositTxV2) { tx.Mint = nil }},
+		{"mint", func(tx *DepositTxV2) { tx.Mint = new(big.Int).Add(tx.Mint, common.Big1) }},
+		{"value", func(tx *DepositTxV2) { tx.Value = new(big.Int).Add(tx.Value, common.Big1) }},
+		{"system flag", func(tx *DepositTxV2) { tx.IsSystemTransaction = !tx.IsSystemTransaction }},
+		{"nil to", func(tx *DepositTxV2) { tx.To = nil }},
+		{"to", func(tx *DepositTxV2) { tx.To = &common.Address{0xff} }},
+		{"source hash", func(tx *DepositTxV2) { tx.SourceHash = common.Hash{0xff} }},
+		{"from", func(tx *DepositTxV2) { tx.From = common.Address{0xff} }},
+		{"gas", func(tx *DepositTxV2) { tx.Gas++ }},
+		{"data", func(tx *DepositTxV2) { tx.Data = append(tx.Data, 0xff) }},
+	} {
+		a, b := base(), base()
+		tc.modify(b)
+		if a.Equal(b) || b.Equal(a) {
+			t.Errorf("%s: differing deposits are equal", tc.name)
+		}
+	}
+	var nilTx *DepositTxV2
+	if !nilTx.Equal(nil) || nilTx.Equal(base()) || base().Equal(nil) {
+		t.Error("nil deposit comparison mismatch")
+	}
+}
+
+func TestDepositTxV2Nonce(t *testing.T) {
+	dep := newTestDepositTxV2()
+	for _, tt := range []struct {
+		inner TxData
+		want  uint64
+	}{
+		{&dep.DepositTx, 0},
+		{dep, 0},
+		{&depositTxWithNonce{DepositTx: dep.DepositTx, EffectiveNonce: 42}, 42},
+		{&depositTxV2WithNonce{DepositTxV2: *dep, EffectiveNonce: 42}, 42},
+	} {
+		tx := &Transaction{inner: tt.inner}
+		if have := tx.Nonce(); have != tt.want {
+			t.Errorf("%T: nonce mismatch: have %d, want %d", tt.inner, have, tt.want)
+		}
+	}
+}
+
+// TestDepositTxJSONNonce checks that a V1 deposit decoded from JSON, as received
+// by the RPC and txpool, reports the nonce it carries from Nonce.
+func TestDepositTxJSONNonce(t *testing.T) {
+	bare, err := json.Marshal(NewTx(&newTestDepositTxV2().DepositTx))
+	if err != nil {
+		t.Fatalf("failed to marshal: %v", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(bare, &fields); err != nil {
+		t.Fatalf("failed to unmarshal fields: %v", err)
+	}
+	fields["nonce"] = json.RawMessage(`"0x2a"`)
+	withNonce, err := json.Marshal(fields)
+	if err != nil {
+		t.Fatalf("failed to marshal fields: %v", err)
+	}
+
+	for _, tt := range []struct {
+		name  string
+		input []byte
+		want  uint64
+	}{
+		{"bare", bare, 0},
+		{"nonce", withNonce, 42},
+	} {
+		var tx Transaction
+		if err := tx.UnmarshalJSON(tt.input); err != nil {
+			t.Fatalf("%s: failed to unmarshal: %v", tt.name, err)
+		}
+		if tx.Type() != DepositTxType {
+			t.Fatalf("%s: type mismatch: have %#x, want %#x", tt.name, tx.Type(), DepositTxType)
+		}
+		if have := tx.Nonce(); have != tt.want {
+			t.Errorf("%s: nonce mismatch: have %d, want %d", tt.name, have, tt.want)
+		}
+		// Only the deposit carrying a nonce reports an effective one
+		if have := tx.EffectiveNonce(); (have == nil) != (tt.want == 0) || (have != nil && *have != tt.want) {
+			t.Errorf("%s: effective nonce mismatch: have %v, want %d", tt.name, have, tt.want)
+		}
+	}
+}
+
+func TestTxTypeBytes(t *testing.T) {
+	if DepositTxV2Type != 0x7d || DepositTxType != 0x7e {
+		t.Fatalf("deposit type bytes changed: have %#x and %#x", DepositTxV2Type, DepositTxType)
+	}
+	seen := make(map[byte]string)
+	for name, typ := range map[string]byte{
+		"LegacyTxType":     LegacyTxType,
+		"AccessListTxType": AccessListTxType,
+		"DynamicFeeTxType": DynamicFeeTxType,
+		"BlobTxType":       BlobTxType,
+		"DepositTxType":    DepositTxType,
+		"DepositTxV2Type":  DepositTxV2Type,
+	} {
+		if other, ok := seen[typ]; ok {
+			t.Errorf("type byte %#x shared by %s and %s", typ, name, other)
+		}
+		seen[typ] = name
+	}
+	// The deposit dispatch must agree on every possible type byte
+	for i := 0; i < 256; i++ {
+		typ := byte(i)
+		inner := newDepositTxData(typ)
+		if isDepositTxType(typ) != (inner != nil) {
+			t.Errorf("type %#x: deposit classification mismatch", typ)
+		}
+		if inner != nil && inner.txType() != typ {
+			t.Errorf("type %#x: payload reports type %#x", typ, inner.txType())
+		}
+	}
+}
+
+func TestDepositTxV2MergeDepositFields(t *testing.T) {
+	tx := NewTx(newTestDepositTxV2())
+	hash := tx.Hash()
+
+	// A nil nonce leaves the bare deposit untouched
+	merged, err := tx.MergeDepositFields(nil)
+	if err != nil {
+		t.Fatalf("failed to merge nil nonce: %v", err)
+	}
+	if merged != tx || tx.EffectiveNonce() != nil {
+		t.Fatal("nil nonce attached an effective nonce")
+	}
+	// Attaching a nonce wraps a copy of the deposit
+	nonce := uint64(7)
+	wrapped, err := tx.MergeDepositFields(&nonce)
+	if err != nil {
+		t.Fatalf("failed to attach nonce: %v", err)
+	}
+	if _, ok := wrapped.inner.(*depositTxV2WithNonce); !ok {
+		t.Fatalf("inner has type %T, want *depositTxV2WithNonce", wrapped.inner)
+	}
+	if have := wrapped.EffectiveNonce(); have == nil || *have != 7 {
+		t.Errorf("attached nonce mismatch: have %v, want 7", have)
+	}
+	if tx.EffectiveNonce() != nil {
+		t.Error("attaching a nonce modified the original transaction")
+	}
+	// Updating replaces the nonce without nesting wrappers
+	nonce = 8
+	updated, err := wrapped.MergeDepositFields(&nonce)
+	if err != nil {
+		t.Fatalf("failed to update nonce: %v", err)
+	}
+	if _, ok := updated.inner.(*depositTxV2WithNonce); !ok {
+		t.Fatalf("inner has type %T, want *depositTxV2WithNonce", updated.inner)
+	}
+	if have := updated.EffectiveNonce(); have == nil || *have != 8 {
+		t.Errorf("updated nonce mismatch: have %v, want 8", have)
+	}
+	if have := wrapped.EffectiveNonce(); have == nil || *have != 7 {
+		t.Errorf("updating modified the original transaction: have %v, want 7", have)
+	}
+	if wrapped.Hash() != hash || updated.Hash() != hash {
+		t.Error("merging changed the transaction hash")
+	}
+	// Non-deposits cannot carry deposit fields
+	legacy := NewTransaction(0, common.Address{}, big.NewInt(0), 21000, big.NewInt(1), nil)
+	if _, err := legacy.MergeDepositFields(&nonce); !errors.Is(err, ErrInvalidTxType) {
+		t.Errorf("legacy: have %v, want %v", err, ErrInvalidTxType)
+	}
+}
+
+func TestSupportedTxTypes(t *testing.T) {
+	list := SupportedTxTypes()
+	seen := make(map[byte]bool)
+	for _, typ := range list {
+		if seen[typ] {
+			t.Errorf("duplicate type byte %#x", typ)
+		}
+		seen[typ] = true
+	}
+	if !seen[DepositTxV2Type] {
+		t.Errorf("DepositTxV2Type missing from %x", list)
+	}
+	// Every typed envelope byte must be decodable iff it is listed
+	for i := 1; i < 256; i++ {
+		typ := byte(i)
+		_, err := new(Transaction).decodeTyped([]byte{typ, 0xc0})
+		if supported := err != ErrTxTypeNotSupported; supported != seen[typ] {
+			t.Errorf("type %#x: decoder support %v, listed %v", typ, supported, seen[typ])
+		}
+	}
+}
+
+func TestSortDepositsBySourceHash(t *testing.T) {
+	var (
+		txs    []*Transaction
+		hashes []common.Hash
+	)
+	for i := 0; i < 16; i++ {
+		hash := crypto.Keccak256Hash([]byte{byte(i)})
+		hashes = append(hashes, hash)
+		txs = append(txs, NewTx(&DepositTxV2{DepositTx{SourceHash: hash, Value: big.NewInt(0), Gas: 50000}}))
+	}
+	legacy := NewTx(&LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000})
+	dynamic := NewTx(&DynamicFeeTx{Nonce: 2, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1), Gas: 21000})
+	txs = append([]*Transaction{legacy}, txs...)
+	txs = append(txs[:8], append([]*Transaction{dynamic}, txs[8:]...)...)
+	rand.New(rand.NewSource(1)).Shuffle(len(txs), func(i, j int) { txs[i], txs[j] = txs[j], txs[i] })
+
+	// Non-deposits keep their relative order after the shuffle
+	var others []*Transaction
+	for _, tx := range txs {
+		if !tx.IsDepositTx() {
+			others = append(others, tx)
+		}
+	}
+	SortDepositsBySourceHash(txs)
+
+	slices.SortFunc(hashes, func(a, b common.Hash) int { return bytes.Compare(a[:], b[:]) })
+	for i, hash := range hashes {
+		if have := txs[i].SourceHash(); have != hash {
+			t.Errorf("deposit %d: source hash mismatch: have %v, want %v", i, have, hash)
+		}
+	}
+	for i, tx := range txs[len(hashes):] {
+		if tx != others[i] {
+			t.Errorf("non-deposit %d: order changed: have %v, want %v", i, tx.Hash(), others[i].Hash())
+		}
+	}
+}
+
+func TestWithDepositReceiptVersion(t *testing.T) {
+	for i, tx := range []*Transaction{newTestDepositTxV2Tx(), newTestDepositTxV2Tx(WithNonce(7))} {
+		if tx.DepositReceiptVersion() != nil {
+			t.Fatalf("variant %d: unexpected receipt version %d", i, *tx.DepositReceiptVersion())
+		}
+		annotated, err := tx.WithDepositReceiptVersion(CanyonDepositReceiptVersion + 1)
+		if err != nil {
+			t.Fatalf("variant %d: failed to set receipt version: %v", i, err)
+		}
+		// Hash the annotated copy first, so the hash is not inherited from the cache
+		if have, want := annotated.Hash(), tx.Hash(); have != want {
+			t.Errorf("variant %d: hash mismatch: have %v, want %v", i, have, want)
+		}
+		if have := annotated.DepositReceiptVersion(); have == nil || *have != CanyonDepositReceiptVersion+1 {
+			t.Errorf("variant %d: receipt version mismatch: have %v, want %d", i, have, CanyonDepositReceiptVersion+1)
+		}
+		if tx.DepositReceiptVersion() != nil {
+			t.Errorf("variant %d: original transaction modified", i)
+		}
+		// The annotation follows the effective nonce being attached or stripped
+		stripped := annotated.WithoutEffectiveNonce()
+		if have := stripped.DepositReceiptVersion(); have == nil || *have != CanyonDepositReceiptVersion+1 {
+			t.Errorf("variant %d: receipt version lost when stripping nonce: have %v", i, have)
+		}
+		wrapped, err := stripped.WithEffectiveNonce(9)
+		if err != nil {
+			t.Fatalf("variant %d: failed to attach nonce: %v", i, err)
+		}
+		if have := wrapped.DepositReceiptVersion(); have == nil || *have != CanyonDepositReceiptVersion+1 {
+			t.Errorf("variant %d: receipt version lost when attaching nonce: have %v", i, have)
+		}
+	}
+	legacy := NewTx(&LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000})
+	if _, err := legacy.WithDepositReceiptVersion(1); !errors.Is(err, ErrInvalidTxType) {
+		t.Errorf("non-deposit error mismatch: have %v, want %v", err, ErrInvalidTxType)
+	}
+}
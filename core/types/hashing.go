@@ -132,3 +132,38 @@ func DeriveSha(list DerivableList, hasher TrieHasher) common.Hash {
 	}
 	return hasher.Hash()
 }
+
+// IncrementalTxRoot computes the transactions root of a block while transactions
+// are appended one at a time, e.g. when inserting deposits during block building.
+//
+// Appended indices do not arrive in trie key order, so the hasher must accept
+// out-of-order updates (e.g. a regular in-memory trie, but not a StackTrie).
+type IncrementalTxRoot struct {
+	hasher TrieHasher
+	count  uint64
+	key    []byte
+	buf    bytes.Buffer
+}
+
+// NewIncrementalTxRoot creates an incremental transactions root calculator on
+// top of the given hasher, resetting it first.
+func NewIncrementalTxRoot(hasher TrieHasher) *IncrementalTxRoot {
+	hasher.Reset()
+	return &IncrementalTxRoot{hasher: hasher}
+}
+
+// Append inserts the next transaction of the block into the trie.
+func (r *IncrementalTxRoot) Append(tx *Transaction) {
+	r.buf.Reset()
+	Transactions{tx}.EncodeIndex(0, &r.buf)
+
+	// The hasher may hold onto the value, so it must not alias the scratch buffer.
+	r.key = rlp.AppendUint64(r.key[:0], r.count)
+	r.hasher.Update(r.key, common.CopyBytes(r.buf.Bytes()))
+	r.count++
+}
+
+// Root returns the transactions root of all transactions appended so far.
+func (r *IncrementalTxRoot) Root() common.Hash {
+	return r.hasher.Hash()
+}
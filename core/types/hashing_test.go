@@ -229,3 +229,43 @@ func (d *hashToHumanReadable) Update(i []byte, i2 []byte) error {
 func (d *hashToHumanReadable) Hash() common.Hash {
 	return common.Hash{}
 }
+
+func TestIncrementalTxRoot(t *testing.T) {
+	var (
+		addr = common.HexToAddress("0x1234567890123456789012345678901234567890")
+		txs  types.Transactions
+	)
+	// Interleave deposits with regular transactions, crossing the 0x7f and 0x80
+	// index boundaries where the trie key ordering changes.
+	for i := 0; i < 300; i++ {
+		dep := types.DepositTx{
+			SourceHash: common.BigToHash(big.NewInt(int64(i + 1))),
+			From:       addr,
+			To:         &addr,
+			Mint:       big.NewInt(int64(i)),
+			Value:      big.NewInt(0),
+			Gas:        50000,
+		}
+		switch i % 3 {
+		case 0:
+			txs = append(txs, types.NewTx(&dep))
+		case 1:
+			txs = append(txs, types.NewTx(&types.DepositTxV2{DepositTx: dep}))
+		default:
+			txs = append(txs, types.NewTransaction(uint64(i), addr, big.NewInt(1), 21000, big.NewInt(1), nil))
+		}
+	}
+	root := types.NewIncrementalTxRoot(trie.NewEmpty(triedb.NewDatabase(rawdb.NewMemoryDatabase(), nil)))
+	if have, want := root.Root(), types.EmptyTxsHash; have != want {
+		t.Fatalf("empty root mismatch: have %x, want %x", have, want)
+	}
+	for i, tx := range txs {
+		root.Append(tx)
+		if i%50 != 0 && i != len(txs)-1 {
+			continue
+		}
+		if have, want := root.Root(), types.DeriveSha(txs[:i+1], trie.NewStackTrie(nil)); have != want {
+			t.Fatalf("root mismatch after %d txs: have %x, want %x", i+1, have, want)
+		}
+	}
+}
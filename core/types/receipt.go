@@ -545,6 +545,19 @@ func (rs Receipts) DeriveFields(config *params.ChainConfig, hash common.Hash, nu
 		rs[i].BlockNumber = new(big.Int).SetUint64(number)
 		rs[i].TransactionIndex = uint(i)
 
+		// Deposit nonces are recorded by the state transition from Regolith onwards.
+		// Receipts lacking them can be completed from nonce-carrying deposits, e.g.
+		// transactions decoded from RPC responses.
+		if txs[i].IsDepositTx() && rs[i].DepositNonce == nil && config.IsOptimismRegolith(time) {
+			if nonce := txs[i].EffectiveNonce(); nonce != nil {
+				rs[i].DepositNonce = nonce
+				if rs[i].DepositReceiptVersion == nil && config.IsOptimismCanyon(time) {
+					version := CanyonDepositReceiptVersion
+					rs[i].DepositReceiptVersion = &version
+				}
+			}
+		}
+
 		// The contract address can be derived from the transaction itself
 		if txs[i].To() == nil {
 			// Deriving the signer is expensive, only do if it's actually needed
@@ -26,6 +26,7 @@ import (
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/holiman/uint256"
@@ -1059,3 +1060,77 @@ func TestRoundTripReceiptForStorage(t *testing.T) {
 		})
 	}
 }
+
+func TestDeriveDepositV2ReceiptNonce(t *testing.T) {
+	zero := uint64(0)
+	config := &params.ChainConfig{
+		ChainID:      big.NewInt(10),
+		LondonBlock:  big.NewInt(0),
+		BedrockBlock: big.NewInt(0),
+		RegolithTime: &zero,
+		CanyonTime:   &zero,
+		Optimism:     &params.OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50},
+	}
+	addr := testDepositAddr
+	wrapped := newTestDepositTxV2Tx(WithNilTo(), WithNonce(42))
+	receipts := Receipts{{Type: DepositTxV2Type, Status: ReceiptStatusSuccessful, CumulativeGasUsed: 50000, Logs: []*Log{}}}
+	if err := receipts.DeriveFields(config, common.Hash{1}, 1, 2, big.NewInt(1), nil, []*Transaction{wrapped}); err != nil {
+		t.Fatalf("DeriveFields failed: %v", err)
+	}
+	r := receipts[0]
+	if r.DepositNonce == nil || *r.DepositNonce != 42 {
+		t.Fatalf("deposit nonce mismatch: have %v, want 42", r.DepositNonce)
+	}
+	if r.DepositReceiptVersion == nil || *r.DepositReceiptVersion != CanyonDepositReceiptVersion {
+		t.Fatalf("deposit receipt version mismatch: have %v, want %d", r.DepositReceiptVersion, CanyonDepositReceiptVersion)
+	}
+	// Creation deposits derive their contract address from the effective nonce
+	if want := crypto.CreateAddress(addr, 42); r.ContractAddress != want {
+		t.Errorf("contract address mismatch: have %v, want %v", r.ContractAddress, want)
+	}
+	enc, err := json.Marshal(r)
+	if err != nil {
+		t.Fatalf("failed to marshal receipt: %v", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(enc, &fields); err != nil {
+		t.Fatalf("failed to unmarshal receipt: %v", err)
+	}
+	if string(fields["depositNonce"]) != `"0x2a"` || string(fields["depositReceiptVersion"]) != `"0x1"` {
+		t.Errorf("deposit fields missing from JSON: %s", enc)
+	}
+
+	// Non-deposit receipts never carry the deposit fields
+	legacy := Receipts{{Type: LegacyTxType, Status: ReceiptStatusSuccessful, CumulativeGasUsed: 21000, Logs: []*Log{}}}
+	if err := legacy.DeriveFields(config, common.Hash{1}, 1, 2, big.NewInt(1), nil, []*Transaction{NewTransaction(0, addr, big.NewInt(0), 21000, big.NewInt(1), nil)}); err != nil {
+		t.Fatalf("DeriveFields failed: %v", err)
+	}
+	if enc, _ := json.Marshal(legacy[0]); bytes.Contains(enc, []byte("deposit")) {
+		t.Errorf("non-deposit receipt JSON carries deposit fields: %s", enc)
+	}
+}
+
+func TestDeriveDepositV2ReceiptEffectiveGasPrice(t *testing.T) {
+	config := &params.ChainConfig{
+		ChainID:      big.NewInt(10),
+		LondonBlock:  big.NewInt(0),
+		BedrockBlock: big.NewInt(0),
+		Optimism:     &params.OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50},
+	}
+	tx := newTestDepositTxV2Tx()
+	for _, tt := range []struct {
+		baseFee *big.Int
+		want    *big.Int
+	}{
+		{big.NewInt(1_234_567), big.NewInt(1_234_567)},
+		{nil, big.NewInt(0)},
+	} {
+		receipts := Receipts{{Type: DepositTxV2Type, Status: ReceiptStatusSuccessful, CumulativeGasUsed: 50000, Logs: []*Log{}}}
+		if err := receipts.DeriveFields(config, common.Hash{1}, 1, 2, tt.baseFee, nil, []*Transaction{tx}); err != nil {
+			t.Fatalf("DeriveFields failed: %v", err)
+		}
+		if have := receipts[0].EffectiveGasPrice; have == nil || have.Cmp(tt.want) != 0 {
+			t.Errorf("base fee %v: effective gas price mismatch: have %v, want %v", tt.baseFee, have, tt.want)
+		}
+	}
+}
@@ -317,7 +317,7 @@ func extractL1GasParamsPreEcotone(config *params.ChainConfig, time uint64, data
 // extractL1GasParamsPostBluebird extracts the gas parameters necessary to compute gas from L1 attribute
 // info calldata after the Bluebird upgrade. The calldata is 292 bytes long (4 byte selector + 288 bytes of data).
 func extractL1GasParamsPostBluebird(data []byte) (gasParams, error) {
-	if len(data) != 292 {
+	if len(data) != L1InfoBluebirdLen {
 		return gasParams{}, fmt.Errorf("expected 292 L1 info bytes in Bluebird, got %d", len(data))
 	}
 	// data layout for Bluebird (288 bytes of data after 4-byte selector):
@@ -351,6 +351,102 @@ func extractL1GasParamsPostBluebird(data []byte) (gasParams, error) {
 	}, nil
 }
 
+// L1InfoBluebirdLen is the fixed length of the L1 info deposit calldata after the
+// Bluebird upgrade, including the 4 byte selector.
+const L1InfoBluebirdLen = 292
+
+// L1InfoDepositData holds the L1 attributes carried by the L1 info system deposit
+// after the Bluebird upgrade. The uint128 FCT fields must fit in 16 bytes, and all
+// other integers in their ABI-packed widths, for the data to be encodable.
+type L1InfoDepositData struct {
+	BaseFeeScalar     uint32
+	BlobBaseFeeScalar uint32
+	SequenceNumber    uint64
+	Time              uint64
+	Number            uint64
+	BaseFee           *big.Int
+	BlobBaseFee       *big.Int
+	BlockHash         common.Hash
+	BatcherHash       common.Hash
+
+	FCTMintPeriodL1DataGas    *big.Int
+	FCTMintRate               *big.Int
+	FCTPeriodStartBlock       *big.Int
+	FCTTotalMinted            *big.Int
+	FCTMaxSupply              *big.Int
+	FCTPeriodMinted           *big.Int
+	FCTInitialTargetPerPeriod *big.Int
+}
+
+// EncodeL1InfoDepositData packs the L1 attributes into the Bluebird L1 info deposit
+// calldata, using the layout documented in extractL1GasParamsPostBluebird. Like
+// Ecotone, the call is prefixed with EcotoneL1AttributesSelector. Nil integers
+// are encoded as zero.
+func EncodeL1InfoDepositData(info *L1InfoDepositData) ([]byte, error) {
+	data := make([]byte, L1InfoBluebirdLen)
+	copy(data[0:4], EcotoneL1AttributesSelector)
+	binary.BigEndian.PutUint32(data[4:8], info.BaseFeeScalar)
+	binary.BigEndian.PutUint32(data[8:12], info.BlobBaseFeeScalar)
+	binary.BigEndian.PutUint64(data[12:20], info.SequenceNumber)
+	binary.BigEndian.PutUint64(data[20:28], info.Time)
+	binary.BigEndian.PutUint64(data[28:36], info.Number)
+	copy(data[100:132], info.BlockHash[:])
+	copy(data[132:164], info.BatcherHash[:])
+
+	for _, field := range []struct {
+		name       string
+		value      *big.Int
+		start, end int
+	}{
+		{"basefee", info.BaseFee, 36, 68},
+		{"blobBaseFee", info.BlobBaseFee, 68, 100},
+		{"fct_mint_period_l1_data_gas", info.FCTMintPeriodL1DataGas, 164, 180},
+		{"fct_mint_rate", info.FCTMintRate, 180, 196},
+		{"fct_period_start_block", info.FCTPeriodStartBlock, 196, 212},
+		{"fct_total_minted", info.FCTTotalMinted, 212, 228},
+		{"fct_max_supply", info.FCTMaxSupply, 228, 244},
+		{"fct_period_minted", info.FCTPeriodMinted, 244, 260},
+		{"fct_initial_target_per_period", info.FCTInitialTargetPerPeriod, 260, 292},
+	} {
+		if field.value == nil {
+			continue
+		}
+		if field.value.Sign() < 0 || field.value.BitLen() > 8*(field.end-field.start) {
+			return nil, fmt.Errorf("L1 info %s out of range: %v", field.name, field.value)
+		}
+		field.value.FillBytes(data[field.start:field.end])
+	}
+	return data, nil
+}
+
+// DecodeL1InfoDepositData unpacks Bluebird L1 info deposit calldata produced by
+// EncodeL1InfoDepositData. Like extractL1GasParamsPostBluebird, only the length is
+// checked, not the selector.
+func DecodeL1InfoDepositData(data []byte) (*L1InfoDepositData, error) {
+	if len(data) != L1InfoBluebirdLen {
+		return nil, fmt.Errorf("expected %d L1 info bytes in Bluebird, got %d", L1InfoBluebirdLen, len(data))
+	}
+	return &L1InfoDepositData{
+		BaseFeeScalar:     binary.BigEndian.Uint32(data[4:8]),
+		BlobBaseFeeScalar: binary.BigEndian.Uint32(data[8:12]),
+		SequenceNumber:    binary.BigEndian.Uint64(data[12:20]),
+		Time:              binary.BigEndian.Uint64(data[20:28]),
+		Number:            binary.BigEndian.Uint64(data[28:36]),
+		BaseFee:           new(big.Int).SetBytes(data[36:68]),
+		BlobBaseFee:       new(big.Int).SetBytes(data[68:100]),
+		BlockHash:         common.BytesToHash(data[100:132]),
+		BatcherHash:       common.BytesToHash(data[132:164]),
+
+		FCTMintPeriodL1DataGas:    new(big.Int).SetBytes(data[164:180]),
+		FCTMintRate:               new(big.Int).SetBytes(data[180:196]),
+		FCTPeriodStartBlock:       new(big.Int).SetBytes(data[196:212]),
+		FCTTotalMinted:            new(big.Int).SetBytes(data[212:228]),
+		FCTMaxSupply:              new(big.Int).SetBytes(data[228:244]),
+		FCTPeriodMinted:           new(big.Int).SetBytes(data[244:260]),
+		FCTInitialTargetPerPeriod: new(big.Int).SetBytes(data[260:292]),
+	}, nil
+}
+
 // extractL1GasParamsPostEcotone extracts the gas parameters necessary to compute gas from L1 attribute
 // info calldata after the Ecotone upgrade, but not for the very first Ecotone block.
 func extractL1GasParamsPostEcotone(data []byte) (gasParams, error) {
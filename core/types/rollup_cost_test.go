@@ -444,3 +444,75 @@ func TestExtractBluebirdGasParams(t *testing.T) {
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "expected 292 L1 info bytes in Bluebird")
 }
+
+func TestL1InfoDepositDataRoundTrip(t *testing.T) {
+	maxUint128 := new(big.Int).Sub(new(big.Int).Lsh(common.Big1, 128), common.Big1)
+	for _, info := range []*L1InfoDepositData{
+		{
+			BaseFeeScalar:             2,
+			BlobBaseFeeScalar:         3,
+			SequenceNumber:            5,
+			Time:                      1_700_000_000,
+			Number:                    19_000_000,
+			BaseFee:                   big.NewInt(30_000_000_000),
+			BlobBaseFee:               big.NewInt(1),
+			BlockHash:                 common.HexToHash("0xdeadbeef"),
+			BatcherHash:               common.HexToHash("0xcafe"),
+			FCTMintPeriodL1DataGas:    big.NewInt(1_000_000),
+			FCTMintRate:               big.NewInt(42),
+			FCTPeriodStartBlock:       big.NewInt(18_999_000),
+			FCTTotalMinted:            big.NewInt(123_456_789),
+			FCTMaxSupply:              maxUint128,
+			FCTPeriodMinted:           big.NewInt(0),
+			FCTInitialTargetPerPeriod: new(big.Int).Lsh(common.Big1, 255),
+		},
+		{
+			BaseFee:                   new(big.Int),
+			BlobBaseFee:               new(big.Int),
+			FCTMintPeriodL1DataGas:    new(big.Int),
+			FCTMintRate:               new(big.Int),
+			FCTPeriodStartBlock:       new(big.Int),
+			FCTTotalMinted:            new(big.Int),
+			FCTMaxSupply:              new(big.Int),
+			FCTPeriodMinted:           new(big.Int),
+			FCTInitialTargetPerPeriod: new(big.Int),
+		},
+	} {
+		data, err := EncodeL1InfoDepositData(info)
+		require.NoError(t, err)
+		require.Len(t, data, L1InfoBluebirdLen)
+		require.Equal(t, EcotoneL1AttributesSelector, data[:4])
+
+		dec, err := DecodeL1InfoDepositData(data)
+		require.NoError(t, err)
+		require.Equal(t, info.Number, dec.Number)
+		require.Equal(t, info.Time, dec.Time)
+		require.Equal(t, info.SequenceNumber, dec.SequenceNumber)
+		require.Equal(t, info.BlockHash, dec.BlockHash)
+		require.Zero(t, info.BaseFee.Cmp(dec.BaseFee))
+		require.Zero(t, info.FCTInitialTargetPerPeriod.Cmp(dec.FCTInitialTargetPerPeriod))
+
+		// Every other field must survive as well
+		reenc, err := EncodeL1InfoDepositData(dec)
+		require.NoError(t, err)
+		require.Equal(t, data, reenc)
+
+		// The gas parameters must be readable from the same payload
+		gp, err := extractL1GasParamsPostBluebird(data)
+		require.NoError(t, err)
+		require.Zero(t, info.BaseFee.Cmp(gp.l1BaseFee))
+		require.Equal(t, info.BaseFeeScalar, *gp.l1BaseFeeScalar)
+	}
+}
+
+func TestL1InfoDepositDataInvalid(t *testing.T) {
+	tooLarge := new(big.Int).Lsh(common.Big1, 128)
+	_, err := EncodeL1InfoDepositData(&L1InfoDepositData{FCTMintRate: tooLarge})
+	require.ErrorContains(t, err, "fct_mint_rate out of range")
+
+	_, err = EncodeL1InfoDepositData(&L1InfoDepositData{BaseFee: big.NewInt(-1)})
+	require.ErrorContains(t, err, "basefee out of range")
+
+	_, err = DecodeL1InfoDepositData(make([]byte, L1InfoBluebirdLen-1))
+	require.ErrorContains(t, err, "expected 292 L1 info bytes")
+}
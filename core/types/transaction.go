@@ -37,6 +37,7 @@ var (
 	ErrUnexpectedProtection = errors.New("transaction type does not supported EIP-155 protected signatures")
 	ErrInvalidTxType        = errors.New("transaction type not valid in this context")
 	ErrTxTypeNotSupported   = errors.New("transaction type not supported")
+	ErrTxTypeNotSigned      = errors.New("transaction type does not carry a signature")
 	ErrGasFeeCapTooLow      = errors.New("fee cap less than base fee")
 	errShortTypedTx         = errors.New("typed transaction too short")
 	errInvalidYParity       = errors.New("'yParity' field must be 0 or 1")
@@ -52,11 +53,21 @@ const (
 	BlobTxType       = 0x03
 )
 
+// SupportedTxTypes returns the type bytes of every transaction type recognized by
+// this package, including the deposit types. The returned slice is fresh and may
+// be modified by the caller.
+func SupportedTxTypes() []byte {
+	return []byte{LegacyTxType, AccessListTxType, DynamicFeeTxType, BlobTxType, DepositTxType, DepositTxV2Type}
+}
+
 // Transaction is an Ethereum transaction.
 type Transaction struct {
 	inner TxData    // Consensus contents of a transaction
 	time  time.Time // Time first seen locally (spam avoidance)
 
+	// Deposit receipt version stamped by derivation, not part of the encoding
+	depositReceiptVersion *uint64
+
 	// caches
 	hash atomic.Pointer[common.Hash]
 	size atomic.Uint64
@@ -211,12 +222,10 @@ func (tx *Transaction) decodeTyped(b []byte) (TxData, error) {
 		inner = new(DynamicFeeTx)
 	case BlobTxType:
 		inner = new(BlobTx)
-	case DepositTxType:
-		inner = new(DepositTx)
-	case DepositTxV2Type:
-		inner = new(DepositTxV2)
 	default:
-		return nil, ErrTxTypeNotSupported
+		if inner = newDepositTxData(b[0]); inner == nil {
+			return nil, ErrTxTypeNotSupported
+		}
 	}
 	err := inner.decode(b[1:])
 	return inner, err
@@ -266,13 +275,14 @@ func isProtectedV(V *big.Int) bool {
 	return true
 }
 
-// Protected says whether the transaction is replay-protected.
+// Protected says whether the transaction is replay-protected. Deposits carry no
+// signature and are never reported as protected.
 func (tx *Transaction) Protected() bool {
 	switch tx := tx.inner.(type) {
 	case *LegacyTx:
 		return tx.V != nil && isProtectedV(tx.V)
 	default:
-		return true
+		return !isDepositTxType(tx.txType())
 	}
 }
 
@@ -283,12 +293,16 @@ func (tx *Transaction) Type() uint8 {
 
 // ChainId returns the EIP155 chain ID of the transaction. The return value will always be
 // non-nil. For legacy transactions which are not replay-protected, the return value is
-// zero.
+// zero. Deposit transactions of all versions, nonce-wrapped or not, carry no chain
+// ID and also report zero.
 func (tx *Transaction) ChainId() *big.Int {
 	return tx.inner.chainID()
 }
 
-// Data returns the input data of the transaction.
+// Data returns the input data of the transaction. The returned slice is shared
+// with the transaction, for deposits and all other types alike, and must not be
+// modified: doing so would corrupt the transaction and, before the hash is cached,
+// its hash. Constructors such as NewTx copy the data passed in.
 func (tx *Transaction) Data() []byte { return tx.inner.data() }
 
 // AccessList returns the access list of the transaction.
@@ -365,10 +379,81 @@ func (tx *Transaction) Mint() *big.Int {
 	return nil
 }
 
+// DepositInner returns the fields of a deposit transaction of any version, with
+// mint and value as copies, so the transaction cannot be modified through them.
+// The mint is nil if the deposit does not mint. For non-deposits, ok is false.
+func (tx *Transaction) DepositInner() (sourceHash common.Hash, from common.Address, mint, value *big.Int, gas uint64, isSystem bool, ok bool) {
+	var dep *DepositTx
+	switch itx := tx.inner.(type) {
+	case *DepositTx:
+		dep = itx
+	case *DepositTxV2:
+		dep = &itx.DepositTx
+	case *depositTxWithNonce:
+		dep = &itx.DepositTx
+	case *depositTxV2WithNonce:
+		dep = &itx.DepositTx
+	default:
+		return common.Hash{}, common.Address{}, nil, nil, 0, false, false
+	}
+	if dep.Mint != nil {
+		mint = new(big.Int).Set(dep.Mint)
+	}
+	value = new(big.Int)
+	if dep.Value != nil {
+		value.Set(dep.Value)
+	}
+	return dep.SourceHash, dep.From, mint, value, dep.Gas, dep.IsSystemTransaction, true
+}
+
+// DepositFields holds the fields of a deposit transaction of any version, as
+// returned by Transaction.DepositFields.
+type DepositFields struct {
+	SourceHash common.Hash
+	From       common.Address
+	To         *common.Address // nil for contract creations
+	Mint       *big.Int        // nil if the deposit does not mint
+	Value      *big.Int
+	Gas        uint64
+	IsSystemTx bool
+	Nonce      *uint64 // effective nonce, only set for nonce-wrapped deposits
+}
+
+// DepositFields returns the fields of a deposit transaction as independent
+// copies, for marshalling deposits without switching over their versions. It
+// returns nil for non-deposit transactions.
+func (tx *Transaction) DepositFields() *DepositFields {
+	sourceHash, from, mint, value, gas, isSystem, ok := tx.DepositInner()
+	if !ok {
+		return nil
+	}
+	fields := &DepositFields{
+		SourceHash: sourceHash,
+		From:       from,
+		To:         tx.To(),
+		Mint:       mint,
+		Value:      value,
+		Gas:        gas,
+		IsSystemTx: isSystem,
+	}
+	if nonce := tx.EffectiveNonce(); nonce != nil {
+		fields.Nonce = new(uint64)
+		*fields.Nonce = *nonce
+	}
+	return fields
+}
+
 // IsDepositTx returns true if the transaction is a deposit tx type.
 func (tx *Transaction) IsDepositTx() bool {
-	t := tx.Type()
-	return t == DepositTxType || t == DepositTxV2Type
+	return isDepositTxType(tx.Type())
+}
+
+// IsDepositWithNonce reports whether the transaction is a Bluebird deposit in its
+// nonce-wrapped form, as opposed to a bare one. Legacy deposits always report
+// false, even when they carry an effective nonce.
+func (tx *Transaction) IsDepositWithNonce() bool {
+	_, ok := tx.inner.(*depositTxV2WithNonce)
+	return ok
 }
 
 // IsSystemTx returns true for deposits that are system transactions. These transactions
@@ -378,7 +463,15 @@ func (tx *Transaction) IsSystemTx() bool {
 }
 
 // Cost returns (gas * gasPrice) + (blobGas * blobGasPrice) + value.
+//
+// Deposit transactions carry no gas price of their own, so their cost is just the
+// value; any gas charged during execution is priced at the block base fee. The
+// minted amount is credited to the sender before execution rather than deducted,
+// and is not part of the cost.
 func (tx *Transaction) Cost() *big.Int {
+	if tx.IsDepositTx() {
+		return tx.Value()
+	}
 	total := new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(tx.Gas()))
 	if tx.Type() == BlobTxType {
 		total.Add(total, new(big.Int).Mul(tx.BlobGasFeeCap(), new(big.Int).SetUint64(tx.BlobGas())))
@@ -579,7 +672,10 @@ func (tx *Transaction) Time() time.Time {
 	return tx.time
 }
 
-// Hash returns the transaction hash.
+// Hash returns the transaction hash. It is the keccak256 of the canonical typed
+// encoding, except for V2 deposits, whose hash is computed as if Mint were unset,
+// so that it does not depend on the mint. A V2 deposit without a mint therefore
+// hashes to the keccak256 of its MarshalBinary output.
 func (tx *Transaction) Hash() common.Hash {
 	if h := tx.hash.Load(); h != nil {
 		return *h
@@ -591,18 +687,14 @@ func (tx *Transaction) Hash() common.Hash {
 		out = rlpHash(tx.inner)
 
 	case DepositTxV2Type:
-		// Copy the transaction and zero out Mint field only
-		var d DepositTx
 		switch inner := tx.inner.(type) {
 		case *DepositTxV2:
-			d = inner.DepositTx
+			out = inner.HashKey()
 		case *depositTxV2WithNonce:
-			d = inner.DepositTxV2.DepositTx
+			out = inner.HashKey()
 		default:
 			panic(fmt.Sprintf("expected DepositTxV2 or depositTxV2WithNonce, got %T", tx.inner))
 		}
-		d.Mint = nil
-		out = prefixedRlpHash(tx.Type(), &d)
 
 	default:
 		out = prefixedRlpHash(tx.Type(), tx.inner)
@@ -17,10 +17,13 @@
 package types
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"math/big"
+	"strconv"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
@@ -29,18 +32,42 @@ import (
 	"github.com/holiman/uint256"
 )
 
-// encodeDepositJSON handles JSON encoding for deposit transactions
-func encodeDepositJSON(enc *txJSON, d *DepositTx) {
-	enc.Gas = (*hexutil.Uint64)(&d.Gas)
-	enc.Value = (*hexutil.Big)(d.Value)
-	enc.Input = (*hexutil.Bytes)(&d.Data)
-	enc.To = d.To
-	enc.SourceHash = &d.SourceHash
-	enc.From = &d.From
-	if d.Mint != nil {
-		enc.Mint = (*hexutil.Big)(d.Mint)
+// depositTxJSON is the JSON representation of deposit transactions. Deposits carry
+// no signature, chain ID or fee fields, so those are omitted entirely rather than
+// encoded as null.
+type depositTxJSON struct {
+	Type       txTypeJSON      `json:"type"`
+	Nonce      *hexutil.Uint64 `json:"nonce,omitempty"`
+	To         *common.Address `json:"to"`
+	Gas        hexutil.Uint64  `json:"gas"`
+	Value      *hexutil.Big    `json:"value"`
+	Input      hexutil.Bytes   `json:"input"`
+	SourceHash common.Hash     `json:"sourceHash"`
+	From       common.Address  `json:"from"`
+	Mint       *hexutil.Big    `json:"mint,omitempty"`
+	IsSystemTx bool            `json:"isSystemTx"`
+	Hash       common.Hash     `json:"hash"`
+}
+
+// encodeDepositJSON handles JSON encoding for deposit transactions. The nonce is
+// only set for deposits carrying an effective nonce.
+func encodeDepositJSON(tx *Transaction, d *DepositTx) *depositTxJSON {
+	enc := &depositTxJSON{
+		Type:       txTypeJSON(tx.Type()),
+		To:         d.To,
+		Gas:        hexutil.Uint64(d.Gas),
+		Value:      (*hexutil.Big)(d.Value),
+		Input:      d.Data,
+		SourceHash: d.SourceHash,
+		From:       d.From,
+		Mint:       (*hexutil.Big)(d.Mint),
+		IsSystemTx: d.IsSystemTransaction,
+		Hash:       tx.Hash(),
 	}
-	enc.IsSystemTx = &d.IsSystemTransaction
+	if nonce := tx.EffectiveNonce(); nonce != nil {
+		enc.Nonce = (*hexutil.Uint64)(nonce)
+	}
+	return enc
 }
 
 // decodeDepositJSON handles JSON decoding for deposit transactions
@@ -77,9 +104,36 @@ func decodeDepositJSON(dec *txJSON, d *DepositTx) error {
 	return nil
 }
 
+// txTypeJSON is the JSON representation of a transaction type. It is encoded as
+// a hex quantity, but also decodes from a decimal string such as "125", which
+// some tooling emits.
+type txTypeJSON hexutil.Uint64
+
+// MarshalText implements encoding.TextMarshaler.
+func (t txTypeJSON) MarshalText() ([]byte, error) {
+	return hexutil.Uint64(t).MarshalText()
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (t *txTypeJSON) UnmarshalJSON(input []byte) error {
+	var s string
+	if err := json.Unmarshal(input, &s); err != nil {
+		return fmt.Errorf("invalid transaction type: %w", err)
+	}
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		return (*hexutil.Uint64)(t).UnmarshalText([]byte(s))
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid transaction type %q: %w", s, err)
+	}
+	*t = txTypeJSON(v)
+	return nil
+}
+
 // txJSON is the JSON representation of transactions.
 type txJSON struct {
-	Type hexutil.Uint64 `json:"type"`
+	Type txTypeJSON `json:"type"`
 
 	ChainID              *hexutil.Big    `json:"chainId,omitempty"`
 	Nonce                *hexutil.Uint64 `json:"nonce"`
@@ -135,10 +189,21 @@ func (tx *txJSON) yParityValue() (*big.Int, error) {
 
 // MarshalJSON marshals as JSON with a hash.
 func (tx *Transaction) MarshalJSON() ([]byte, error) {
+	// Deposits use a dedicated encoding without signature and fee fields.
+	switch itx := tx.inner.(type) {
+	case *DepositTx:
+		return json.Marshal(encodeDepositJSON(tx, itx))
+	case *depositTxWithNonce:
+		return json.Marshal(encodeDepositJSON(tx, &itx.DepositTx))
+	case *DepositTxV2:
+		return json.Marshal(encodeDepositJSON(tx, &itx.DepositTx))
+	case *depositTxV2WithNonce:
+		return json.Marshal(encodeDepositJSON(tx, &itx.DepositTx))
+	}
 	var enc txJSON
 	// These are set for all tx types.
 	enc.Hash = tx.Hash()
-	enc.Type = hexutil.Uint64(tx.Type())
+	enc.Type = txTypeJSON(tx.Type())
 
 	// Other fields are set conditionally depending on tx type.
 	switch itx := tx.inner.(type) {
@@ -209,12 +274,6 @@ func (tx *Transaction) MarshalJSON() ([]byte, error) {
 			enc.Commitments = itx.Sidecar.Commitments
 			enc.Proofs = itx.Sidecar.Proofs
 		}
-
-	case *DepositTx:
-		encodeDepositJSON(&enc, itx)
-		
-	case *DepositTxV2:
-		encodeDepositJSON(&enc, &itx.DepositTx)
 	}
 	return json.Marshal(&enc)
 }
@@ -492,20 +551,25 @@ func (tx *Transaction) UnmarshalJSON(input []byte) error {
 			if err := decodeDepositJSON(&dec, &itx); err != nil {
 				return err
 			}
+			inner = &itx
 			if dec.Nonce != nil {
-				inner = &depositTxWithNonce{DepositTx: itx, EffectiveNonce: uint64(*dec.Nonce)}
-			} else {
-				inner = &itx
+				if inner, err = wrapDepositNonce(inner, uint64(*dec.Nonce)); err != nil {
+					return err
+				}
 			}
 		} else { // DepositTxV2Type
 			var itx DepositTxV2
 			if err := decodeDepositJSON(&dec, &itx.DepositTx); err != nil {
 				return err
 			}
+			if err := itx.validateAmounts(); err != nil {
+				return err
+			}
+			inner = &itx
 			if dec.Nonce != nil {
-				inner = &depositTxV2WithNonce{DepositTxV2: itx, EffectiveNonce: uint64(*dec.Nonce)}
-			} else {
-				inner = &itx
+				if inner, err = wrapDepositNonce(inner, uint64(*dec.Nonce)); err != nil {
+					return err
+				}
 			}
 		}
 		
@@ -520,6 +584,130 @@ func (tx *Transaction) UnmarshalJSON(input []byte) error {
 	return nil
 }
 
+// UnmarshalJSONStrict decodes a transaction like UnmarshalJSON, but rejects
+// deposit transactions carrying any field outside of the deposit encoding, such
+// as a signature or fee field. Non-deposit types are decoded leniently.
+func (tx *Transaction) UnmarshalJSONStrict(input []byte) error {
+	var head struct {
+		Type txTypeJSON `json:"type"`
+	}
+	if err := json.Unmarshal(input, &head); err != nil {
+		return err
+	}
+	if isDepositTxType(byte(head.Type)) {
+		var dec depositTxJSON
+		d := json.NewDecoder(bytes.NewReader(input))
+		d.DisallowUnknownFields()
+		if err := d.Decode(&dec); err != nil {
+			return err
+		}
+	}
+	// Required fields are checked by the regular decoder.
+	return tx.UnmarshalJSON(input)
+}
+
+// ErrDepositNonceWrapped is returned when attaching an effective nonce to a deposit
+// transaction that already carries one.
+var ErrDepositNonceWrapped = errors.New("deposit transaction already carries an effective nonce")
+
+// wrapDepositNonce attaches an effective nonce to a bare deposit transaction. The
+// nonce wrappers must never be nested, as the wrapped payload is what gets hashed
+// and encoded.
+func wrapDepositNonce(inner TxData, nonce uint64) (TxData, error) {
+	switch itx := inner.(type) {
+	case *DepositTx:
+		return &depositTxWithNonce{DepositTx: *itx, EffectiveNonce: nonce}, nil
+	case *DepositTxV2:
+		return &depositTxV2WithNonce{DepositTxV2: *itx, EffectiveNonce: nonce}, nil
+	case *depositTxWithNonce, *depositTxV2WithNonce:
+		return nil, ErrDepositNonceWrapped
+	default:
+		return nil, ErrInvalidTxType
+	}
+}
+
+// WithEffectiveNonce returns a copy of the deposit transaction carrying the given
+// effective nonce. It fails for non-deposit transactions and for deposits that
+// already carry an effective nonce.
+func (tx *Transaction) WithEffectiveNonce(nonce uint64) (*Transaction, error) {
+	inner, err := wrapDepositNonce(tx.inner.copy(), nonce)
+	if err != nil {
+		return nil, err
+	}
+	return &Transaction{inner: inner, time: tx.time, depositReceiptVersion: tx.depositReceiptVersion}, nil
+}
+
+// MergeDepositFields returns a copy of the deposit transaction carrying a
+// later-learned effective nonce, wrapping a bare deposit or updating the nonce of
+// a wrapped one. A nil nonce returns the transaction itself. Like the other With*
+// methods it never modifies tx, which may be shared, and the hash is not affected.
+func (tx *Transaction) MergeDepositFields(nonce *uint64) (*Transaction, error) {
+	if !tx.IsDepositTx() {
+		return nil, ErrInvalidTxType
+	}
+	if nonce == nil {
+		return tx, nil
+	}
+	var inner TxData
+	switch itx := tx.inner.copy().(type) {
+	case *depositTxWithNonce:
+		itx.EffectiveNonce = *nonce
+		inner = itx
+	case *depositTxV2WithNonce:
+		itx.EffectiveNonce = *nonce
+		inner = itx
+	default:
+		wrapped, err := wrapDepositNonce(itx, *nonce)
+		if err != nil {
+			return nil, err
+		}
+		inner = wrapped
+	}
+	cpy := &Transaction{inner: inner, time: tx.time, depositReceiptVersion: tx.depositReceiptVersion}
+	if h := tx.hash.Load(); h != nil {
+		cpy.hash.Store(h)
+	}
+	return cpy, nil
+}
+
+// WithoutEffectiveNonce returns a copy of the deposit transaction with its
+// effective nonce stripped. Transactions without one are returned unchanged. The
+// hash is not affected, as the effective nonce is not part of the encoding.
+func (tx *Transaction) WithoutEffectiveNonce() *Transaction {
+	switch itx := tx.inner.(type) {
+	case *depositTxWithNonce:
+		return &Transaction{inner: itx.DepositTx.copy(), time: tx.time, depositReceiptVersion: tx.depositReceiptVersion}
+	case *depositTxV2WithNonce:
+		return &Transaction{inner: itx.DepositTxV2.copy(), time: tx.time, depositReceiptVersion: tx.depositReceiptVersion}
+	}
+	return tx
+}
+
+// WithDepositReceiptVersion returns a copy of the deposit transaction annotated
+// with the receipt version that derivation assigned to it. The annotation is not
+// part of the encoding and does not affect the hash, but receipt building records
+// it in place of the Canyon default. It fails for non-deposit transactions.
+func (tx *Transaction) WithDepositReceiptVersion(version uint64) (*Transaction, error) {
+	if !tx.IsDepositTx() {
+		return nil, ErrInvalidTxType
+	}
+	cpy := &Transaction{inner: tx.inner.copy(), time: tx.time, depositReceiptVersion: &version}
+	if h := tx.hash.Load(); h != nil {
+		cpy.hash.Store(h)
+	}
+	return cpy, nil
+}
+
+// DepositReceiptVersion returns the receipt version annotated by
+// WithDepositReceiptVersion, or nil if none was set.
+func (tx *Transaction) DepositReceiptVersion() *uint64 {
+	if tx.depositReceiptVersion == nil {
+		return nil
+	}
+	version := *tx.depositReceiptVersion
+	return &version
+}
+
 type depositTxWithNonce struct {
 	DepositTx
 	EffectiveNonce uint64
@@ -532,16 +720,43 @@ func (tx *depositTxWithNonce) EncodeRLP(w io.Writer) error {
 
 func (tx *depositTxWithNonce) effectiveNonce() *uint64 { return &tx.EffectiveNonce }
 
+// copy creates a deep copy that keeps the wrapper and its effective nonce.
+func (tx *depositTxWithNonce) copy() TxData {
+	return &depositTxWithNonce{DepositTx: *tx.DepositTx.copy().(*DepositTx), EffectiveNonce: tx.EffectiveNonce}
+}
+
+// nonce reports the effective nonce, like depositTxV2WithNonce does.
+func (tx *depositTxWithNonce) nonce() uint64 { return tx.EffectiveNonce }
+
 // depositTxV2WithNonce wraps a V2 deposit transaction with an effective nonce
 type depositTxV2WithNonce struct {
 	DepositTxV2
 	EffectiveNonce uint64
 }
 
+var _ TxData = (*depositTxV2WithNonce)(nil)
+
+// EncodeRLP ensures that RLP encoding this transaction excludes the nonce. Otherwise, the tx Hash would change
 func (tx *depositTxV2WithNonce) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, tx.DepositTxV2)
+	return rlp.Encode(w, &tx.DepositTxV2)
 }
 
 func (tx *depositTxV2WithNonce) effectiveNonce() *uint64 { 
 	return &tx.EffectiveNonce 
 }
+
+// copy creates a deep copy that keeps the wrapper and its effective nonce.
+func (tx *depositTxV2WithNonce) copy() TxData {
+	return &depositTxV2WithNonce{DepositTxV2: *tx.DepositTxV2.copy().(*DepositTxV2), EffectiveNonce: tx.EffectiveNonce}
+}
+
+// String returns a compact, stable summary of the deposit for debugging, like
+// DepositTxV2.String, including the effective nonce.
+func (tx *depositTxV2WithNonce) String() string {
+	return fmt.Sprintf("DepositTxV2{%s, nonce: %d}", tx.fieldsString(), tx.EffectiveNonce)
+}
+
+// nonce reports the effective nonce, so that generic callers of Nonce see the
+// nonce the deposit was executed with, for both deposit versions. Bare deposits
+// report zero.
+func (tx *depositTxV2WithNonce) nonce() uint64 { return tx.EffectiveNonce }
@@ -127,7 +127,16 @@ func MustSignNewTx(prv *ecdsa.PrivateKey, s Signer, txdata TxData) *Transaction
 // Sender may cache the address, allowing it to be used regardless of
 // signing method. The cache is invalidated if the cached signer does
 // not match the signer used in the current call.
+//
+// Deposit transactions carry their sender explicitly, so it is returned without
+// going through the signer, for any signer.
 func Sender(signer Signer, tx *Transaction) (common.Address, error) {
+	if from, ok := tx.depositFrom(); ok {
+		if tx.from.Load() == nil {
+			tx.from.Store(&sigCache{signer: signer, from: from})
+		}
+		return from, nil
+	}
 	if sigCache := tx.from.Load(); sigCache != nil {
 		// If the signer used to derive from in a previous
 		// call is not the same as used current, invalidate
@@ -145,6 +154,22 @@ func Sender(signer Signer, tx *Transaction) (common.Address, error) {
 	return addr, nil
 }
 
+// depositFrom returns the sender stored in a deposit transaction, and whether the
+// transaction is a deposit at all.
+func (tx *Transaction) depositFrom() (common.Address, bool) {
+	switch itx := tx.inner.(type) {
+	case *DepositTx:
+		return itx.From, true
+	case *DepositTxV2:
+		return itx.From, true
+	case *depositTxWithNonce:
+		return itx.From, true
+	case *depositTxV2WithNonce:
+		return itx.From, true
+	}
+	return common.Address{}, false
+}
+
 // Signer encapsulates transaction signature handling. The name of this type is slightly
 // misleading because Signers don't actually sign, they're just for validating and
 // processing of signatures.
@@ -249,17 +274,8 @@ func NewLondonSigner(chainId *big.Int) Signer {
 }
 
 func (s londonSigner) Sender(tx *Transaction) (common.Address, error) {
-	if tx.IsDepositTx() {
-		switch itx := tx.inner.(type) {
-		case *DepositTx:
-			return itx.From, nil
-		case *DepositTxV2:
-			return itx.From, nil
-		case *depositTxWithNonce:
-			return itx.From, nil
-		case *depositTxV2WithNonce:
-			return itx.From, nil
-		}
+	if from, ok := tx.depositFrom(); ok {
+		return from, nil
 	}
 	if tx.Type() != DynamicFeeTxType {
 		return s.eip2930Signer.Sender(tx)
@@ -281,7 +297,7 @@ func (s londonSigner) Equal(s2 Signer) bool {
 
 func (s londonSigner) SignatureValues(tx *Transaction, sig []byte) (R, S, V *big.Int, err error) {
 	if tx.IsDepositTx() {
-		return nil, nil, nil, fmt.Errorf("deposits do not have signatures")
+		return nil, nil, nil, fmt.Errorf("%w: deposit type %#x", ErrTxTypeNotSigned, tx.Type())
 	}
 	txdata, ok := tx.inner.(*DynamicFeeTx)
 	if !ok {
@@ -188,3 +188,60 @@ func createTestLegacyTxInner() *LegacyTx {
 		Data:     nil,
 	}
 }
+
+func TestDepositSignatureValues(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	sig, _ := crypto.Sign(make([]byte, 32), key)
+
+	dep := DepositTx{Value: big.NewInt(0)}
+	for _, inner := range []TxData{&dep, &DepositTxV2{dep}} {
+		for _, signer := range []Signer{NewLondonSigner(big.NewInt(1)), NewCancunSigner(big.NewInt(1))} {
+			_, _, _, err := signer.SignatureValues(NewTx(inner), sig)
+			if !errors.Is(err, ErrTxTypeNotSigned) {
+				t.Errorf("%T with %T: error mismatch: have %v, want %v", inner, signer, err, ErrTxTypeNotSigned)
+			}
+		}
+	}
+}
+
+func TestDepositSender(t *testing.T) {
+	from := testDepositAddr
+	dep := newTestDepositTxV2().DepositTx
+	for _, inner := range []TxData{
+		&dep,
+		&DepositTxV2{dep},
+		&depositTxV2WithNonce{DepositTxV2: DepositTxV2{dep}, EffectiveNonce: 1},
+	} {
+		tx := &Transaction{inner: inner}
+		for _, signer := range []Signer{NewLondonSigner(big.NewInt(1)), NewCancunSigner(big.NewInt(1))} {
+			sender, err := Sender(signer, tx)
+			if err != nil {
+				t.Fatalf("%T with %T: failed to get sender: %v", inner, signer, err)
+			}
+			if sender != from {
+				t.Errorf("%T with %T: sender mismatch: have %v, want %v", inner, signer, sender, from)
+			}
+		}
+		if cache := tx.from.Load(); cache == nil || cache.from != from {
+			t.Errorf("%T: sender not cached", inner)
+		}
+	}
+}
+
+func BenchmarkDepositSender(b *testing.B) {
+	tx := newTestDepositTxV2Tx()
+	signers := []Signer{NewLondonSigner(big.NewInt(1)), NewCancunSigner(big.NewInt(1))}
+
+	b.Run("Sender", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			Sender(signers[i%2], tx)
+		}
+	})
+	b.Run("Signer.Sender", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			signers[i%2].Sender(tx)
+		}
+	})
+}
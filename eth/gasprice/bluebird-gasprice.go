@@ -0,0 +1,56 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// SuggestBaseFee returns the base fee suggested for transactions targeting the
+// block after the latest one: the base fee predicted from the latest header, but
+// never less than the Bluebird minimum if Bluebird is active for the latest block.
+// The floor matters when the latest block itself sits below the minimum, as only
+// base fee decreases are clamped to it. It returns nil before London.
+func (oracle *Oracle) SuggestBaseFee(ctx context.Context) (*big.Int, error) {
+	head, err := oracle.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if head == nil || head.BaseFee == nil {
+		return nil, nil
+	}
+	config := oracle.backend.ChainConfig()
+	return applyBluebirdFloor(config, head, eip1559.CalcBaseFee(config, head, head.Time+1)), nil
+}
+
+// applyBluebirdFloor raises baseFee to the Bluebird minimum base fee if Bluebird
+// is active for head. The given base fee may be modified.
+func applyBluebirdFloor(config *params.ChainConfig, head *types.Header, baseFee *big.Int) *big.Int {
+	if !config.IsBluebird(head.Time) {
+		return baseFee
+	}
+	if floor := new(big.Int).SetUint64(config.MinBaseFee(head.Time)); baseFee.Cmp(floor) < 0 {
+		return baseFee.Set(floor)
+	}
+	return baseFee
+}
@@ -0,0 +1,98 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package gasprice
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// headerTestBackend serves a single latest header.
+type headerTestBackend struct {
+	opTestBackend
+	config *params.ChainConfig
+	head   *types.Header
+}
+
+func (b *headerTestBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*types.Header, error) {
+	return b.head, nil
+}
+
+func (b *headerTestBackend) Pending() (*types.Block, types.Receipts, *state.StateDB) {
+	panic("not implemented")
+}
+
+func (b *headerTestBackend) ChainConfig() *params.ChainConfig {
+	return b.config
+}
+
+func (b *headerTestBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return nil
+}
+
+func TestSuggestBaseFeeBluebirdFloor(t *testing.T) {
+	var (
+		bluebird = uint64(1000)
+		config   = *params.TestChainConfig
+		floor    = new(big.Int).SetUint64(params.BluebirdMinBaseFee)
+	)
+	config.BluebirdTime = &bluebird
+
+	for _, tc := range []struct {
+		name    string
+		time    uint64
+		baseFee int64
+		want    *big.Int
+	}{
+		// A Bluebird block below the floor, e.g. right after activation, is lifted to it
+		{"bluebird below floor", bluebird, 500, floor},
+		{"bluebird above floor", bluebird, 2_000_000_000, big.NewInt(2_000_000_000)},
+		// Without Bluebird there is no floor
+		{"pre-bluebird below floor", bluebird - 10, 500, big.NewInt(500)},
+	} {
+		head := &types.Header{
+			Number:   big.NewInt(10),
+			Time:     tc.time,
+			GasLimit: 30_000_000,
+			GasUsed:  30_000_000 / config.ElasticityMultiplier(tc.time+1), // at target
+			BaseFee:  big.NewInt(tc.baseFee),
+		}
+		backend := &headerTestBackend{config: &config, head: head}
+		oracle := NewOracle(backend, Config{Blocks: 1, Percentile: 60}, nil)
+
+		have, err := oracle.SuggestBaseFee(context.Background())
+		if err != nil {
+			t.Fatalf("%s: failed to suggest base fee: %v", tc.name, err)
+		}
+		if have.Cmp(tc.want) != 0 {
+			t.Errorf("%s: suggestion mismatch: have %v, want %v", tc.name, have, tc.want)
+		}
+	}
+	// Pre-London chains have no base fee to suggest
+	backend := &headerTestBackend{config: &config, head: &types.Header{Number: big.NewInt(1)}}
+	if have, err := NewOracle(backend, Config{Blocks: 1}, nil).SuggestBaseFee(context.Background()); have != nil || err != nil {
+		t.Errorf("pre-London suggestion: have %v, %v", have, err)
+	}
+}
@@ -176,9 +176,10 @@ type StdTraceConfig struct {
 
 // txTraceResult is the result of a single transaction trace.
 type txTraceResult struct {
-	TxHash common.Hash `json:"txHash"`           // transaction hash
-	Result interface{} `json:"result,omitempty"` // Trace results produced by the tracer
-	Error  string      `json:"error,omitempty"`  // Trace failure produced by the tracer
+	TxHash  common.Hash `json:"txHash"`            // transaction hash
+	Deposit bool        `json:"deposit,omitempty"` // Whether the transaction is a deposit, attributed to its own From
+	Result  interface{} `json:"result,omitempty"`  // Trace results produced by the tracer
+	Error   string      `json:"error,omitempty"`   // Trace failure produced by the tracer
 }
 
 // blockTraceTask represents a single block trace task when an entire chain is
@@ -280,11 +281,11 @@ func (api *API) traceChain(start, end *types.Block, config *TraceConfig, closed
 					}
 					res, err := api.traceTx(ctx, tx, msg, txctx, blockCtx, task.statedb, config)
 					if err != nil {
-						task.results[i] = &txTraceResult{TxHash: tx.Hash(), Error: err.Error()}
+						task.results[i] = &txTraceResult{TxHash: tx.Hash(), Deposit: tx.IsDepositTx(), Error: err.Error()}
 						log.Warn("Tracing failed", "hash", tx.Hash(), "block", task.block.NumberU64(), "err", err)
 						break
 					}
-					task.results[i] = &txTraceResult{TxHash: tx.Hash(), Result: res}
+					task.results[i] = &txTraceResult{TxHash: tx.Hash(), Deposit: tx.IsDepositTx(), Result: res}
 				}
 				// Tracing state is used up, queue it for de-referencing. Note the
 				// state is the parent state of trace block, use block.number-1 as
@@ -656,7 +657,7 @@ func (api *API) traceBlock(ctx context.Context, block *types.Block, config *Trac
 		if err != nil {
 			return nil, err
 		}
-		results[i] = &txTraceResult{TxHash: tx.Hash(), Result: res}
+		results[i] = &txTraceResult{TxHash: tx.Hash(), Deposit: tx.IsDepositTx(), Result: res}
 	}
 	return results, nil
 }
@@ -698,10 +699,10 @@ func (api *API) traceBlockParallel(ctx context.Context, block *types.Block, stat
 				blockCtx := core.NewEVMBlockContext(block.Header(), api.chainContext(ctx), nil, api.backend.ChainConfig(), task.statedb)
 				res, err := api.traceTx(ctx, txs[task.index], msg, txctx, blockCtx, task.statedb, config)
 				if err != nil {
-					results[task.index] = &txTraceResult{TxHash: txs[task.index].Hash(), Error: err.Error()}
+					results[task.index] = &txTraceResult{TxHash: txs[task.index].Hash(), Deposit: txs[task.index].IsDepositTx(), Error: err.Error()}
 					continue
 				}
-				results[task.index] = &txTraceResult{TxHash: txs[task.index].Hash(), Result: res}
+				results[task.index] = &txTraceResult{TxHash: txs[task.index].Hash(), Deposit: txs[task.index].IsDepositTx(), Result: res}
 			}
 		}()
 	}
@@ -27,6 +27,7 @@ import (
 	"net/http"
 	"reflect"
 	"slices"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -40,6 +41,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -588,6 +590,57 @@ func TestTraceTransactionHistorical(t *testing.T) {
 	}
 }
 
+func TestTraceBlockDepositTxV2(t *testing.T) {
+	// Report the sender handed to the tracer, bypassing signature recovery
+	DefaultDirectory.Register("depositFromTracer", func(ctx *Context, cfg json.RawMessage) (*Tracer, error) {
+		var from common.Address
+		return &Tracer{
+			Hooks: &tracing.Hooks{
+				OnTxStart: func(vm *tracing.VMContext, tx *types.Transaction, sender common.Address) { from = sender },
+			},
+			GetResult: func() (json.RawMessage, error) { return json.Marshal(from) },
+			Stop:      func(err error) {},
+		}, nil
+	}, false)
+
+	var (
+		zero     = uint64(0)
+		config   = *params.OptimismTestConfig
+		accounts = newAccounts(1)
+	)
+	config.BedrockBlock = big.NewInt(0)
+	config.RegolithTime = &zero
+	genesis := &core.Genesis{
+		Config: &config,
+		Alloc:  types.GenesisAlloc{accounts[0].addr: {Balance: big.NewInt(params.Ether)}},
+	}
+	var deposit *types.Transaction
+	backend := newTestBackend(t, 1, genesis, func(i int, b *core.BlockGen) {
+		deposit = types.NewTx(&types.DepositTxV2{DepositTx: types.DepositTx{
+			SourceHash: common.HexToHash("0xdeadbeef"),
+			From:       accounts[0].addr,
+			To:         &accounts[0].addr,
+			Mint:       big.NewInt(1000),
+			Value:      big.NewInt(0),
+			Gas:        params.TxGas,
+		}})
+		b.AddTx(deposit)
+	})
+	defer backend.chain.Stop()
+	api := NewAPI(backend)
+
+	tracer := "depositFromTracer"
+	result, err := api.TraceBlockByNumber(context.Background(), rpc.BlockNumber(1), &TraceConfig{Tracer: &tracer})
+	if err != nil {
+		t.Fatalf("failed to trace block: %v", err)
+	}
+	have, _ := json.Marshal(result)
+	want := fmt.Sprintf(`[{"txHash":"%v","deposit":true,"result":"%v"}]`, deposit.Hash(), strings.ToLower(accounts[0].addr.Hex()))
+	if string(have) != want {
+		t.Errorf("result mismatch, have\n%v\n, want\n%v\n", string(have), want)
+	}
+}
+
 func TestTraceBlock(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,128 @@
+// Copyright 2024 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package deposittest provides the Bluebird deposit fixture for tests outside of
+// core/types. It mirrors the fixture core/types uses internally, which cannot be
+// shared with it without an import cycle.
+package deposittest
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Addr is the default sender and recipient of fixture deposits.
+var Addr = common.HexToAddress("0x1234567890123456789012345678901234567890")
+
+// deposit collects the fixture settings applied by Options.
+type deposit struct {
+	dep   *types.DepositTxV2
+	nonce *uint64
+}
+
+// Option customizes a fixture deposit.
+type Option func(*deposit)
+
+// WithSourceHash sets the source hash of the fixture deposit.
+func WithSourceHash(hash common.Hash) Option {
+	return func(d *deposit) { d.dep.SourceHash = hash }
+}
+
+// WithFrom sets the sender of the fixture deposit.
+func WithFrom(from common.Address) Option {
+	return func(d *deposit) { d.dep.From = from }
+}
+
+// WithTo sets the recipient of the fixture deposit.
+func WithTo(to common.Address) Option {
+	return func(d *deposit) { d.dep.To = &to }
+}
+
+// WithMint sets the mint of the fixture deposit, nil meaning none.
+func WithMint(mint *big.Int) Option {
+	return func(d *deposit) { d.dep.Mint = mint }
+}
+
+// WithValue sets the value transferred by the fixture deposit.
+func WithValue(value *big.Int) Option {
+	return func(d *deposit) { d.dep.Value = value }
+}
+
+// WithGas sets the gas limit of the fixture deposit.
+func WithGas(gas uint64) Option {
+	return func(d *deposit) { d.dep.Gas = gas }
+}
+
+// WithData sets the calldata of the fixture deposit.
+func WithData(data []byte) Option {
+	return func(d *deposit) { d.dep.Data = data }
+}
+
+// WithNilTo turns the fixture deposit into a contract creation.
+func WithNilTo() Option {
+	return func(d *deposit) { d.dep.To = nil }
+}
+
+// WithSystem marks the fixture deposit as a system deposit.
+func WithSystem() Option {
+	return func(d *deposit) { d.dep.IsSystemTransaction = true }
+}
+
+// WithNonce attaches an effective nonce to the transaction built by NewTx. It
+// has no effect on NewDepositTxV2.
+func WithNonce(nonce uint64) Option {
+	return func(d *deposit) { d.nonce = &nonce }
+}
+
+func build(opts []Option) *deposit {
+	to := Addr
+	d := &deposit{dep: &types.DepositTxV2{DepositTx: types.DepositTx{
+		SourceHash: common.HexToHash("0xdeadbeef"),
+		From:       Addr,
+		To:         &to,
+		Mint:       big.NewInt(1000),
+		Value:      big.NewInt(2000),
+		Gas:        50000,
+		Data:       []byte("test data"),
+	}}}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// NewDepositTxV2 creates a user deposit with fixed fixture values, tweaked by
+// the given options.
+func NewDepositTxV2(opts ...Option) *types.DepositTxV2 {
+	return build(opts).dep
+}
+
+// NewTx wraps a fixture deposit into a transaction, carrying the effective nonce
+// if one was set with WithNonce.
+func NewTx(opts ...Option) *types.Transaction {
+	d := build(opts)
+	tx := types.NewTx(d.dep)
+	if d.nonce == nil {
+		return tx
+	}
+	wrapped, err := tx.WithEffectiveNonce(*d.nonce)
+	if err != nil {
+		panic(err)
+	}
+	return wrapped
+}
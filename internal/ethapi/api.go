@@ -19,6 +19,7 @@ package ethapi
 import (
 	"context"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
@@ -1483,6 +1484,22 @@ type RPCTransaction struct {
 	DepositReceiptVersion *hexutil.Uint64 `json:"depositReceiptVersion,omitempty"`
 }
 
+// MarshalJSON marshals the transaction like the default encoding, except that
+// V2 deposits omit the signature fields they don't carry, as the JSON encoding
+// of the transactions themselves does.
+func (tx RPCTransaction) MarshalJSON() ([]byte, error) {
+	type rpcTransaction RPCTransaction
+	if tx.Type != types.DepositTxV2Type {
+		return json.Marshal(rpcTransaction(tx))
+	}
+	return json.Marshal(struct {
+		rpcTransaction
+		V *hexutil.Big `json:"v,omitempty"`
+		R *hexutil.Big `json:"r,omitempty"`
+		S *hexutil.Big `json:"s,omitempty"`
+	}{rpcTransaction: rpcTransaction(tx), V: tx.V, R: tx.R, S: tx.S})
+}
+
 // newRPCTransaction returns a transaction that will serialize to the RPC
 // representation, with the given location metadata set (if available).
 func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber uint64, blockTime uint64, index uint64, baseFee *big.Int, config *params.ChainConfig, receipt *types.Receipt) *RPCTransaction {
@@ -1519,6 +1536,15 @@ func newRPCTransaction(tx *types.Transaction, blockHash common.Hash, blockNumber
 			result.IsSystemTx = &isSystemTx
 		}
 		result.Mint = (*hexutil.Big)(tx.Mint())
+		// Prefer the receipt's deposit nonce below, but fall back to any nonce
+		// carried by the transaction itself
+		if nonce := tx.EffectiveNonce(); nonce != nil {
+			result.Nonce = hexutil.Uint64(*nonce)
+		}
+		if tx.Type() == types.DepositTxV2Type {
+			// V2 deposits carry no signature, so don't report made-up zero values
+			result.V, result.R, result.S = nil, nil, nil
+		}
 		if receipt != nil && receipt.DepositNonce != nil {
 			result.Nonce = hexutil.Uint64(*receipt.DepositNonce)
 			if receipt.DepositReceiptVersion != nil {
@@ -2331,6 +2357,61 @@ func (api *DebugAPI) GetRawTransaction(ctx context.Context, hash common.Hash) (h
 	return tx.MarshalBinary()
 }
 
+// AdminBlockStatsResult summarizes the deposit and fee-market properties of a block.
+type AdminBlockStatsResult struct {
+	Number         hexutil.Uint64 `json:"number"`
+	DepositCount   hexutil.Uint64 `json:"depositCount"`
+	DepositGasUsed hexutil.Uint64 `json:"depositGasUsed"`
+	TotalMint      *hexutil.Big   `json:"totalMint"`
+	BaseFee        *hexutil.Big   `json:"baseFee,omitempty"`
+	BaseFeeFloored bool           `json:"baseFeeFloored"`
+	Elasticity     hexutil.Uint64 `json:"elasticity"`
+	Bluebird       bool           `json:"bluebird"`
+}
+
+// AdminBlockStats assembles the block statistics of the given block. The receipts
+// are optional and only used for the gas consumed by deposits. A base fee counts
+// as floored if it sits exactly at the Bluebird minimum.
+func AdminBlockStats(config *params.ChainConfig, header *types.Header, txs types.Transactions, receipts types.Receipts) AdminBlockStatsResult {
+	stats := AdminBlockStatsResult{
+		Number:     hexutil.Uint64(header.Number.Uint64()),
+		TotalMint:  (*hexutil.Big)(new(big.Int)),
+		Elasticity: hexutil.Uint64(config.ElasticityMultiplier(header.Time)),
+		Bluebird:   config.IsBluebird(header.Time),
+	}
+	for i, tx := range txs {
+		if !tx.IsDepositTx() {
+			continue
+		}
+		stats.DepositCount++
+		if mint := tx.Mint(); mint != nil {
+			stats.TotalMint.ToInt().Add(stats.TotalMint.ToInt(), mint)
+		}
+		if i < len(receipts) {
+			stats.DepositGasUsed += hexutil.Uint64(receipts[i].GasUsed)
+		}
+	}
+	if header.BaseFee != nil {
+		stats.BaseFee = (*hexutil.Big)(new(big.Int).Set(header.BaseFee))
+		stats.BaseFeeFloored = stats.Bluebird && header.BaseFee.Cmp(new(big.Int).SetUint64(config.MinBaseFee(header.Time))) == 0
+	}
+	return stats
+}
+
+// GetBlockStats returns the deposit and fee-market statistics of a single block.
+func (api *DebugAPI) GetBlockStats(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*AdminBlockStatsResult, error) {
+	block, err := api.b.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if block == nil || err != nil {
+		return nil, err
+	}
+	receipts, err := api.b.GetReceipts(ctx, block.Hash())
+	if err != nil {
+		return nil, err
+	}
+	stats := AdminBlockStats(api.b.ChainConfig(), block.Header(), block.Transactions(), receipts)
+	return &stats, nil
+}
+
 // PrintBlock retrieves a block and returns its pretty printed form.
 func (api *DebugAPI) PrintBlock(ctx context.Context, number uint64) (string, error) {
 	block, _ := api.b.BlockByNumber(ctx, rpc.BlockNumber(number))
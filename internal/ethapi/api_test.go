@@ -117,6 +117,35 @@ func TestRPCTransactionDepositTxWithVersion(t *testing.T) {
 	require.Equal(t, "0x1", parsed["depositReceiptVersion"])
 }
 
+func TestRPCTransactionSignatureFields(t *testing.T) {
+	// Regular transactions always report their signature values
+	tx := types.NewTx(&types.LegacyTx{Nonce: 1, GasPrice: big.NewInt(1), Gas: 21000})
+	b, err := json.Marshal(newRPCTransaction(tx, common.Hash{}, 12, 1234, 1, big.NewInt(0), &params.ChainConfig{ChainID: big.NewInt(1)}, nil))
+	require.NoError(t, err, "marshalling failed: %w", err)
+	parsed := make(map[string]json.RawMessage)
+	require.NoError(t, json.Unmarshal(b, &parsed))
+	for _, key := range []string{"v", "r", "s"} {
+		require.Equal(t, `"0x0"`, string(parsed[key]), "signature field %q", key)
+	}
+	// Even if unset, regular transactions keep the fields, as before
+	b, err = json.Marshal(&RPCTransaction{Type: types.LegacyTxType})
+	require.NoError(t, err, "marshalling failed: %w", err)
+	parsed = make(map[string]json.RawMessage)
+	require.NoError(t, json.Unmarshal(b, &parsed))
+	for _, key := range []string{"v", "r", "s"} {
+		require.Equal(t, "null", string(parsed[key]), "signature field %q", key)
+	}
+	// Legacy deposits keep reporting zero signature values
+	tx = types.NewTx(&types.DepositTx{From: common.HexToAddress("0x5678"), Value: big.NewInt(0)})
+	b, err = json.Marshal(newRPCTransaction(tx, common.Hash{}, 12, 1234, 1, big.NewInt(0), &params.ChainConfig{ChainID: big.NewInt(1)}, nil))
+	require.NoError(t, err, "marshalling failed: %w", err)
+	parsed = make(map[string]json.RawMessage)
+	require.NoError(t, json.Unmarshal(b, &parsed))
+	for _, key := range []string{"v", "r", "s"} {
+		require.Equal(t, `"0x0"`, string(parsed[key]), "signature field %q", key)
+	}
+}
+
 func TestNewRPCTransactionOmitIsSystemTxFalse(t *testing.T) {
 	tx := types.NewTx(&types.DepositTx{
 		IsSystemTransaction: false,
@@ -126,6 +155,106 @@ func TestNewRPCTransactionOmitIsSystemTxFalse(t *testing.T) {
 	require.Nil(t, got.IsSystemTx, "should omit IsSystemTx when false")
 }
 
+func TestNewRPCPendingTransactionDepositTxV2(t *testing.T) {
+	tx := types.NewTx(&types.DepositTxV2{DepositTx: types.DepositTx{
+		SourceHash: common.HexToHash("0x1234"),
+		From:       common.HexToAddress("0x5678"),
+		Gas:        21000,
+		Value:      big.NewInt(1),
+		Mint:       big.NewInt(34),
+	}})
+	header := &types.Header{Number: big.NewInt(12), Time: 1234, BaseFee: big.NewInt(7)}
+	got := NewRPCPendingTransaction(tx, header, &params.ChainConfig{ChainID: big.NewInt(1)})
+
+	require.Nil(t, got.V, "should omit V for V2 deposits")
+	require.Nil(t, got.R, "should omit R for V2 deposits")
+	require.Nil(t, got.S, "should omit S for V2 deposits")
+	require.Equal(t, common.HexToAddress("0x5678"), got.From)
+
+	b, err := json.Marshal(got)
+	require.NoError(t, err, "marshalling failed: %w", err)
+	parsed := make(map[string]json.RawMessage)
+	require.NoError(t, json.Unmarshal(b, &parsed))
+	require.Equal(t, `"0x7d"`, string(parsed["type"]))
+	for _, key := range []string{"v", "r", "s", "yParity"} {
+		require.NotContains(t, parsed, key, "unexpected signature field %q", key)
+	}
+
+	// The RPC representation must still decode as the original transaction
+	var decoded types.Transaction
+	require.NoError(t, decoded.UnmarshalJSON(b))
+	require.Equal(t, tx.Hash(), decoded.Hash())
+}
+
+func TestRPCMarshalBlockDepositTxV2(t *testing.T) {
+	t.Parallel()
+	genesis := &core.Genesis{Config: params.MergedTestChainConfig, Alloc: types.GenesisAlloc{}}
+	backend := newTestBackend(t, 0, genesis, beacon.New(ethash.NewFaker()), nil)
+
+	deposit, err := types.NewTx(&types.DepositTxV2{DepositTx: types.DepositTx{
+		SourceHash:          common.HexToHash("0x1234"),
+		From:                common.HexToAddress("0x5678"),
+		Gas:                 21000,
+		Value:               big.NewInt(1),
+		Mint:                big.NewInt(34),
+		IsSystemTransaction: true,
+	}}).WithEffectiveNonce(7)
+	require.NoError(t, err)
+	block := types.NewBlock(&types.Header{Number: big.NewInt(100)}, &types.Body{Transactions: types.Transactions{deposit}}, nil, blocktest.NewHasher())
+
+	resp, err := RPCMarshalBlock(context.Background(), block, true, true, params.MergedTestChainConfig, backend)
+	require.NoError(t, err)
+	out, err := json.Marshal(resp["transactions"])
+	require.NoError(t, err)
+
+	var txs []map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(out, &txs))
+	require.Len(t, txs, 1)
+	for key, want := range map[string]string{
+		"type":       `"0x7d"`,
+		"sourceHash": `"` + common.HexToHash("0x1234").Hex() + `"`,
+		"mint":       `"0x22"`,
+		"isSystemTx": `true`,
+		"nonce":      `"0x7"`,
+	} {
+		require.Equal(t, want, string(txs[0][key]), "field %q", key)
+	}
+}
+
+func TestAdminBlockStats(t *testing.T) {
+	bluebird := uint64(1000)
+	config := &params.ChainConfig{LondonBlock: big.NewInt(0), BluebirdTime: &bluebird}
+	header := &types.Header{
+		Number:  big.NewInt(10),
+		Time:    bluebird + 2,
+		BaseFee: new(big.Int).SetUint64(params.BluebirdMinBaseFee),
+	}
+	txs := types.Transactions{
+		types.NewTx(&types.DepositTx{SourceHash: common.HexToHash("0x01"), Mint: big.NewInt(100), Value: big.NewInt(0)}),
+		types.NewTx(&types.DepositTxV2{DepositTx: types.DepositTx{SourceHash: common.HexToHash("0x02"), Mint: big.NewInt(23), Value: big.NewInt(0)}}),
+		types.NewTx(&types.DepositTxV2{DepositTx: types.DepositTx{SourceHash: common.HexToHash("0x03"), Value: big.NewInt(0)}}),
+		types.NewTransaction(0, common.Address{}, big.NewInt(1), 21000, big.NewInt(1), nil),
+	}
+	receipts := types.Receipts{{GasUsed: 50000}, {GasUsed: 30000}, {GasUsed: 20000}, {GasUsed: 21000}}
+
+	stats := AdminBlockStats(config, header, txs, receipts)
+	require.Equal(t, hexutil.Uint64(10), stats.Number)
+	require.Equal(t, hexutil.Uint64(3), stats.DepositCount)
+	require.Equal(t, hexutil.Uint64(100000), stats.DepositGasUsed)
+	require.Equal(t, big.NewInt(123), stats.TotalMint.ToInt())
+	require.Equal(t, header.BaseFee, stats.BaseFee.ToInt())
+	require.True(t, stats.BaseFeeFloored)
+	require.True(t, stats.Bluebird)
+	require.Equal(t, hexutil.Uint64(params.BluebirdElasticityMultiplier), stats.Elasticity)
+
+	// A base fee above the floor, or any base fee before Bluebird, is not floored
+	header.BaseFee = new(big.Int).SetUint64(params.BluebirdMinBaseFee + 1)
+	require.False(t, AdminBlockStats(config, header, txs, receipts).BaseFeeFloored)
+	header.Time = bluebird - 1
+	header.BaseFee = new(big.Int).SetUint64(params.BluebirdMinBaseFee)
+	require.False(t, AdminBlockStats(config, header, txs, receipts).BaseFeeFloored)
+}
+
 func TestUnmarshalRpcDepositTx(t *testing.T) {
 	version := hexutil.Uint64(types.CanyonDepositReceiptVersion)
 	tests := []struct {
@@ -239,6 +239,11 @@ web3._extend({
 			call: 'debug_getRawReceipts',
 			params: 1
 		}),
+		new web3._extend.Method({
+			name: 'getBlockStats',
+			call: 'debug_getBlockStats',
+			params: 1
+		}),
 		new web3._extend.Method({
 			name: 'getRawTransaction',
 			call: 'debug_getRawTransaction',
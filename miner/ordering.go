@@ -18,6 +18,8 @@ package miner
 
 import (
 	"container/heap"
+	"errors"
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -164,3 +166,27 @@ func (t *transactionsByPriceAndNonce) Empty() bool {
 func (t *transactionsByPriceAndNonce) Clear() {
 	t.heads, t.txs = nil, nil
 }
+
+// errDepositAfterTx is returned by checkDepositsFirst for a deposit that follows
+// a regular transaction.
+var errDepositAfterTx = errors.New("deposit transaction after non-deposit transaction")
+
+// checkDepositsFirst verifies that all deposits, of any version, precede the other
+// transactions of a forced transaction list. The list describes the block to build
+// as derived by the rollup node, so it is rejected rather than reordered: building
+// a different block would hide the derivation bug.
+func checkDepositsFirst(txs types.Transactions) error {
+	regular := -1
+	for i, tx := range txs {
+		if !tx.IsDepositTx() {
+			if regular < 0 {
+				regular = i
+			}
+			continue
+		}
+		if regular >= 0 {
+			return fmt.Errorf("%w: deposit %d (%v) follows transaction %d", errDepositAfterTx, i, tx.Hash(), regular)
+		}
+	}
+	return nil
+}
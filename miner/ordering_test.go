@@ -18,6 +18,7 @@ package miner
 
 import (
 	"crypto/ecdsa"
+	"errors"
 	"math/big"
 	"math/rand"
 	"testing"
@@ -194,3 +195,46 @@ func TestTransactionTimeSort(t *testing.T) {
 		}
 	}
 }
+
+func TestCheckDepositsFirst(t *testing.T) {
+	t.Parallel()
+
+	var (
+		deposits types.Transactions
+		others   types.Transactions
+	)
+	for i := 0; i < 3; i++ {
+		dep := types.DepositTx{SourceHash: common.BigToHash(big.NewInt(int64(i + 1))), Value: new(big.Int), Gas: 50000}
+		var deposit *types.Transaction
+		switch i {
+		case 0:
+			deposit = types.NewTx(&dep)
+		case 1:
+			deposit = types.NewTx(&types.DepositTxV2{DepositTx: dep})
+		default:
+			wrapped, err := types.NewTx(&types.DepositTxV2{DepositTx: dep}).WithEffectiveNonce(uint64(i))
+			if err != nil {
+				t.Fatalf("failed to wrap deposit: %v", err)
+			}
+			deposit = wrapped
+		}
+		other := types.NewTx(&types.LegacyTx{Nonce: uint64(i), GasPrice: big.NewInt(int64(100 - i)), Gas: 21000})
+		deposits, others = append(deposits, deposit), append(others, other)
+	}
+	for _, tc := range []struct {
+		name    string
+		txs     types.Transactions
+		wantErr error
+	}{
+		{"empty", nil, nil},
+		{"deposits only", deposits, nil},
+		{"regular only", others, nil},
+		{"deposits leading", append(append(types.Transactions(nil), deposits...), others...), nil},
+		{"deposit after regular", types.Transactions{deposits[0], others[0], deposits[1], others[1]}, errDepositAfterTx},
+		{"regular first", types.Transactions{others[0], deposits[2]}, errDepositAfterTx},
+	} {
+		if err := checkDepositsFirst(tc.txs); !errors.Is(err, tc.wantErr) {
+			t.Errorf("%s: error mismatch: have %v, want %v", tc.name, err, tc.wantErr)
+		}
+	}
+}
@@ -17,6 +17,7 @@
 package miner
 
 import (
+	"errors"
 	"math/big"
 	"reflect"
 	"testing"
@@ -36,6 +37,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/internal/deposittest"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -325,3 +327,52 @@ func TestPayloadId(t *testing.T) {
 		ids[id] = i
 	}
 }
+
+// TestGenerateWorkDepositRules checks that the miner refuses to build a block out
+// of forced deposits that the Bluebird body validation would reject.
+func TestGenerateWorkDepositRules(t *testing.T) {
+	t.Parallel()
+
+	var (
+		bluebird    = uint64(0)
+		maxDataSize = uint64(16)
+		config      = *params.TestChainConfig
+	)
+	config.BluebirdTime = &bluebird
+	config.StrictSystemDeposits = true
+	config.MaxDepositMint = big.NewInt(1_000_000)
+	config.MaxDepositDataSize = &maxDataSize
+	w, b := newTestWorker(t, &config, ethash.NewFaker(), rawdb.NewMemoryDatabase(), 0)
+
+	for _, tc := range []struct {
+		name    string
+		txs     types.Transactions
+		wantErr error
+	}{
+		{"valid", types.Transactions{deposittest.NewTx()}, nil},
+		{"gas above block limit", types.Transactions{deposittest.NewTx(deposittest.WithGas(b.chain.CurrentBlock().GasLimit * 2))}, core.ErrDepositGasLimitExceeded},
+		{"system value", types.Transactions{deposittest.NewTx(deposittest.WithSystem())}, core.ErrSystemDepositValue},
+		{"zero source hash", types.Transactions{deposittest.NewTx(deposittest.WithSourceHash(common.Hash{}))}, core.ErrDepositZeroSourceHash},
+		{"zero gas", types.Transactions{deposittest.NewTx(deposittest.WithGas(0))}, core.ErrDepositZeroGas},
+		{"duplicate source hash", types.Transactions{deposittest.NewTx(), deposittest.NewTx(deposittest.WithNonce(1))}, core.ErrDepositDuplicateSourceHash},
+		{"mint cap", types.Transactions{deposittest.NewTx(deposittest.WithMint(big.NewInt(1_000_001)))}, core.ErrDepositMintExceeded},
+		{"empty system deposit", types.Transactions{deposittest.NewTx(
+			deposittest.WithSystem(), deposittest.WithMint(nil), deposittest.WithValue(new(big.Int)), deposittest.WithData(nil),
+		)}, core.ErrEmptySystemDeposit},
+		{"data size", types.Transactions{deposittest.NewTx(deposittest.WithData(make([]byte, maxDataSize+1)))}, core.ErrDepositDataTooLarge},
+	} {
+		res := w.generateWork(&generateParams{
+			timestamp: b.chain.CurrentBlock().Time + 1,
+			coinbase:  testBankAddress,
+			txs:       tc.txs,
+			noTxs:     true,
+		})
+		if !errors.Is(res.err, tc.wantErr) {
+			t.Errorf("%s: error mismatch: have %v, want %v", tc.name, res.err, tc.wantErr)
+			continue
+		}
+		if tc.wantErr == nil && len(res.block.Transactions()) != len(tc.txs) {
+			t.Errorf("%s: transaction count mismatch: have %d, want %d", tc.name, len(res.block.Transactions()), len(tc.txs))
+		}
+	}
+}
@@ -119,6 +119,14 @@ func (miner *Miner) generateWork(params *generateParams) *newPayloadResult {
 
 	misc.EnsureCreate2Deployer(miner.chainConfig, work.header.Time, work.state)
 
+	if err := checkDepositsFirst(params.txs); err != nil {
+		return &newPayloadResult{err: err}
+	}
+	// Reject deposits the block body validation would, rather than sealing a block
+	// that neither this node nor its peers will import.
+	if err := core.ValidateDepositTxs(miner.chainConfig, work.header, params.txs); err != nil {
+		return &newPayloadResult{err: err}
+	}
 	for _, tx := range params.txs {
 		from, _ := types.Sender(work.signer, tx)
 		work.state.SetTxContext(tx.Hash(), work.tcount)
@@ -207,7 +215,7 @@ func (miner *Miner) prepareWork(genParams *generateParams) (*environment, error)
 	}
 	// Set baseFee and GasLimit if we are on an EIP-1559 chain
 	if miner.chainConfig.IsLondon(header.Number) {
-		header.BaseFee = eip1559.CalcBaseFee(miner.chainConfig, parent, header.Time)
+		header.BaseFee = eip1559.CalcBaseFeeCached(miner.chainConfig, parent, header.ParentHash, header.Time)
 		if !miner.chainConfig.IsLondon(parent.Number) {
 			parentGasLimit := parent.GasLimit * miner.chainConfig.ElasticityMultiplier(header.Time)
 			header.GasLimit = core.CalcGasLimit(parentGasLimit, miner.config.GasCeil)
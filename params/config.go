@@ -389,6 +389,21 @@ type ChainConfig struct {
 	// Bluebird fork
 	BluebirdTime *uint64 `json:"bluebirdTime,omitempty"` // Bluebird fork time (nil = no fork, 0 = already on bluebird)
 
+	// Optional decay of the Bluebird base fee floor. If set, the floor linearly decays from
+	// BluebirdMinBaseFee to BluebirdFloorDecayTarget over BluebirdFloorDecayWindow seconds
+	// after the Bluebird activation.
+	BluebirdFloorDecayTarget *uint64 `json:"bluebirdFloorDecayTarget,omitempty"` // Steady-state floor (nil = no decay)
+	BluebirdFloorDecayWindow uint64  `json:"bluebirdFloorDecayWindow,omitempty"` // Decay duration in seconds (0 = immediate)
+
+	BluebirdBaseFeeChangeDenominatorOverride *uint64 `json:"bluebirdBaseFeeChangeDenominatorOverride,omitempty"` // Bluebird base fee change denominator (nil or 0 = BluebirdBaseFeeChangeDenominator)
+
+	SystemTxAllowValue bool `json:"systemTxAllowValue,omitempty"` // Whether Bluebird system deposits may transfer value
+
+	MaxDepositMint     *big.Int `json:"maxDepositMint,omitempty"`     // Maximum mint of a single Bluebird deposit (nil = no cap)
+	MaxDepositDataSize *uint64  `json:"maxDepositDataSize,omitempty"` // Maximum calldata size in bytes of a single Bluebird deposit (nil = no limit)
+
+	StrictSystemDeposits bool `json:"strictSystemDeposits,omitempty"` // Whether empty Bluebird system deposits (no mint, value or data) are rejected
+
 	// TerminalTotalDifficulty is the amount of total difficulty reached by
 	// the network that triggers the consensus upgrade.
 	TerminalTotalDifficulty *big.Int `json:"terminalTotalDifficulty,omitempty"`
@@ -829,6 +844,19 @@ func (c *ChainConfig) CheckConfigForkOrder() error {
 			lastFork = cur
 		}
 	}
+	// Bluebird changes the EIP-1559 parameters, so London must precede it. As the
+	// two are scheduled on different axes, only a genesis Bluebird can be checked
+	// against the London block.
+	if c.BluebirdTime != nil {
+		if c.LondonBlock == nil {
+			return fmt.Errorf("unsupported fork ordering: londonBlock not enabled, but bluebirdTime enabled at timestamp %v",
+				*c.BluebirdTime)
+		}
+		if *c.BluebirdTime == 0 && c.LondonBlock.Sign() > 0 {
+			return fmt.Errorf("unsupported fork ordering: londonBlock enabled at block %v, but bluebirdTime enabled at genesis",
+				c.LondonBlock)
+		}
+	}
 	return nil
 }
 
@@ -930,33 +958,166 @@ func (c *ChainConfig) checkCompatible(newcfg *ChainConfig, headNumber *big.Int,
 	return nil
 }
 
+// BaseFeeParams holds the EIP-1559 parameters in effect at a given block time.
+type BaseFeeParams struct {
+	Elasticity  uint64 // Bound on the gas limit relative to the gas target
+	Denominator uint64 // Bound on the base fee change between blocks
+	MinBaseFee  uint64 // Floor the base fee may not drop below, zero if none
+}
+
+// baseFeeParams returns the EIP-1559 parameters of the latest fee-affecting fork
+// active at the given block time. Forks are checked newest first, so scheduling
+// a new set of parameters only requires prepending a case.
+func (c *ChainConfig) baseFeeParams(time uint64) BaseFeeParams {
+	switch {
+	case c.IsBluebird(time):
+		// A zero override is treated as unset rather than dividing by zero
+		denominator := BluebirdBaseFeeChangeDenominator
+		if c.BluebirdBaseFeeChangeDenominatorOverride != nil && *c.BluebirdBaseFeeChangeDenominatorOverride != 0 {
+			denominator = *c.BluebirdBaseFeeChangeDenominatorOverride
+		}
+		return BaseFeeParams{
+			Elasticity:  BluebirdElasticityMultiplier,
+			Denominator: denominator,
+			MinBaseFee:  c.bluebirdMinBaseFee(time),
+		}
+	case c.Optimism != nil:
+		// An unset Canyon denominator is left zero here and rejected by
+		// BaseFeeChangeDenominator, so that callers only needing the elasticity
+		// or the floor are not affected by it.
+		denominator := c.Optimism.EIP1559Denominator
+		if c.IsCanyon(time) {
+			denominator = 0
+			if c.Optimism.EIP1559DenominatorCanyon != nil {
+				denominator = *c.Optimism.EIP1559DenominatorCanyon
+			}
+		}
+		return BaseFeeParams{
+			Elasticity:  c.Optimism.EIP1559Elasticity,
+			Denominator: denominator,
+		}
+	default:
+		return BaseFeeParams{
+			Elasticity:  DefaultElasticityMultiplier,
+			Denominator: DefaultBaseFeeChangeDenominator,
+		}
+	}
+}
+
+// BaseFeeParamsAt returns the EIP-1559 parameters in effect at the given block
+// time, so that callers needing several of them resolve the active fork once. It
+// panics if Canyon is active on an Optimism chain lacking a Canyon denominator.
+func (c *ChainConfig) BaseFeeParamsAt(time uint64) BaseFeeParams {
+	params := c.baseFeeParams(time)
+	if params.Denominator == 0 && c.Optimism != nil && c.IsCanyon(time) {
+		panic("invalid ChainConfig.Optimism.EIP1559DenominatorCanyon value: '0' or 'nil'")
+	}
+	return params
+}
+
 // BaseFeeChangeDenominator bounds the amount the base fee can change between blocks.
 // The time parameters is the timestamp of the block to determine if Canyon is active or not
 func (c *ChainConfig) BaseFeeChangeDenominator(time uint64) uint64 {
+	return c.BaseFeeParamsAt(time).Denominator
+}
+
+// BaseFeeChangeDenominatorInfo returns the base fee change denominator at the given
+// block time, along with a label for fee UIs: "bluebird" once Bluebird is active,
+// and "default" for the pre-Bluebird schedule of the chain, Optimism's included.
+func (c *ChainConfig) BaseFeeChangeDenominatorInfo(time uint64) (uint64, string) {
+	label := "default"
 	if c.IsBluebird(time) {
-		return BluebirdBaseFeeChangeDenominator
+		label = "bluebird"
 	}
-	if c.Optimism != nil {
-		if c.IsCanyon(time) {
-			if c.Optimism.EIP1559DenominatorCanyon == nil || *c.Optimism.EIP1559DenominatorCanyon == 0 {
-				panic("invalid ChainConfig.Optimism.EIP1559DenominatorCanyon value: '0' or 'nil'")
+	return c.BaseFeeChangeDenominator(time), label
+}
+
+// ElasticityMultiplier bounds the maximum gas limit an EIP-1559 block may have.
+func (c *ChainConfig) ElasticityMultiplier(time uint64) uint64 {
+	return c.baseFeeParams(time).Elasticity
+}
+
+// IsFeeParamChangeConsensusBreaking reports whether replacing the EIP-1559 fee
+// parameters of old with those of newcfg changes the base fee computation of any
+// block up to and including the head. Changes that only affect blocks after the
+// head can be rolled out without rewriting history.
+//
+// The two configs alone cannot tell which forks have already activated: the same
+// change to the Bluebird floor is harmless a day before activation and a hard
+// fork a day after. The head block number, for London, and timestamp, for Canyon
+// and Bluebird, are therefore taken like CheckCompatible takes them, and fork
+// reschedules are judged by the same rules it applies.
+func IsFeeParamChangeConsensusBreaking(old, newcfg *ChainConfig, headNumber, headTimestamp uint64) bool {
+	head := new(big.Int).SetUint64(headNumber)
+
+	// Rescheduling an already activated fee-affecting fork is always breaking
+	if isForkBlockIncompatible(old.LondonBlock, newcfg.LondonBlock, head) ||
+		isForkTimestampIncompatible(old.CanyonTime, newcfg.CanyonTime, headTimestamp, nil) ||
+		isForkTimestampIncompatible(old.BluebirdTime, newcfg.BluebirdTime, headTimestamp, nil) {
+		return true
+	}
+	// Optimism parameters apply from London onwards, the Canyon denominator once
+	// Canyon is active
+	if old.IsLondon(head) {
+		if (old.Optimism == nil) != (newcfg.Optimism == nil) {
+			return true
+		}
+		if old.Optimism != nil {
+			if old.Optimism.EIP1559Elasticity != newcfg.Optimism.EIP1559Elasticity ||
+				old.Optimism.EIP1559Denominator != newcfg.Optimism.EIP1559Denominator {
+				return true
+			}
+			if old.IsCanyon(headTimestamp) && !configUint64Equal(old.Optimism.EIP1559DenominatorCanyon, newcfg.Optimism.EIP1559DenominatorCanyon) {
+				return true
 			}
-			return *c.Optimism.EIP1559DenominatorCanyon
 		}
-		return c.Optimism.EIP1559Denominator
 	}
-	return DefaultBaseFeeChangeDenominator
+	// Bluebird parameters only matter once Bluebird is active
+	if old.IsBluebird(headTimestamp) {
+		if !configUint64Equal(old.BluebirdFloorDecayTarget, newcfg.BluebirdFloorDecayTarget) ||
+			old.BluebirdFloorDecayWindow != newcfg.BluebirdFloorDecayWindow ||
+			!configUint64Equal(old.BluebirdBaseFeeChangeDenominatorOverride, newcfg.BluebirdBaseFeeChangeDenominatorOverride) {
+			return true
+		}
+	}
+	return false
 }
 
-// ElasticityMultiplier bounds the maximum gas limit an EIP-1559 block may have.
-func (c *ChainConfig) ElasticityMultiplier(time uint64) uint64 {
-	if c.IsBluebird(time) {
-		return BluebirdElasticityMultiplier
+// MinBaseFee returns the floor the base fee may not drop below at the given block
+// time. It is zero before Bluebird, and decays towards BluebirdFloorDecayTarget if a
+// decay is configured.
+func (c *ChainConfig) MinBaseFee(time uint64) uint64 {
+	return c.baseFeeParams(time).MinBaseFee
+}
+
+// bluebirdMinBaseFee returns the base fee floor at a block time at or after
+// Bluebird activation.
+func (c *ChainConfig) bluebirdMinBaseFee(time uint64) uint64 {
+	if c.BluebirdFloorDecayTarget == nil {
+		return BluebirdMinBaseFee
 	}
-	if c.Optimism != nil {
-		return c.Optimism.EIP1559Elasticity
+	target := *c.BluebirdFloorDecayTarget
+	elapsed := time - *c.BluebirdTime
+	if elapsed >= c.BluebirdFloorDecayWindow {
+		return target
 	}
-	return DefaultElasticityMultiplier
+	// Linearly interpolate between the initial floor and the target, rounding
+	// towards the initial floor.
+	start := new(big.Int).SetUint64(BluebirdMinBaseFee)
+	delta := new(big.Int).Sub(new(big.Int).SetUint64(target), start)
+	delta.Mul(delta, new(big.Int).SetUint64(elapsed))
+	delta.Quo(delta, new(big.Int).SetUint64(c.BluebirdFloorDecayWindow))
+	return start.Add(start, delta).Uint64()
+}
+
+// BluebirdTargetRatio returns the Bluebird gas target as a fraction of the
+// default EIP-1559 gas target for the same gas limit, i.e. the default elasticity
+// multiplier over the Bluebird one. A ratio below one means the target shrank.
+func BluebirdTargetRatio() *big.Rat {
+	return new(big.Rat).SetFrac(
+		new(big.Int).SetUint64(DefaultElasticityMultiplier),
+		new(big.Int).SetUint64(BluebirdElasticityMultiplier),
+	)
 }
 
 // LatestFork returns the latest time-based fork that would be active for the given time.
@@ -1035,6 +1196,15 @@ func configTimestampEqual(x, y *uint64) bool {
 	return *x == *y
 }
 
+// configUint64Equal reports whether two optional config values are equal, i.e.
+// both unset or both set to the same value.
+func configUint64Equal(x, y *uint64) bool {
+	if x == nil || y == nil {
+		return x == y
+	}
+	return *x == *y
+}
+
 // ConfigCompatError is raised if the locally-stored blockchain is initialised with a
 // ChainConfig that would alter the past.
 type ConfigCompatError struct {
@@ -17,6 +17,7 @@
 package params
 
 import (
+	"encoding/json"
 	"fmt"
 	"math/big"
 	"reflect"
@@ -211,3 +212,253 @@ func TestConfigRulesRegolith(t *testing.T) {
 		t.Errorf("expected %v to be regolith", stamp)
 	}
 }
+
+func TestMinBaseFeeDecay(t *testing.T) {
+	var (
+		bluebird = uint64(1000)
+		target   = uint64(200_000)
+		window   = uint64(800)
+	)
+	c := &ChainConfig{BluebirdTime: &bluebird}
+
+	// Without a decay target the floor is constant once Bluebird is active
+	require.Equal(t, uint64(0), c.MinBaseFee(bluebird-1))
+	require.Equal(t, BluebirdMinBaseFee, c.MinBaseFee(bluebird))
+	require.Equal(t, BluebirdMinBaseFee, c.MinBaseFee(bluebird+10*window))
+
+	c.BluebirdFloorDecayTarget = &target
+	c.BluebirdFloorDecayWindow = window
+	require.Equal(t, uint64(0), c.MinBaseFee(bluebird-1), "pre-Bluebird")
+	require.Equal(t, BluebirdMinBaseFee, c.MinBaseFee(bluebird), "start")
+	require.Equal(t, (BluebirdMinBaseFee+target)/2, c.MinBaseFee(bluebird+window/2), "middle")
+	require.Equal(t, target, c.MinBaseFee(bluebird+window), "end")
+	require.Equal(t, target, c.MinBaseFee(bluebird+2*window), "after end")
+
+	// A zero window switches to the target immediately
+	c.BluebirdFloorDecayWindow = 0
+	require.Equal(t, target, c.MinBaseFee(bluebird))
+}
+
+func TestIsFeeParamChangeConsensusBreaking(t *testing.T) {
+	var (
+		bluebird = uint64(1000)
+		later    = uint64(2000)
+		target   = uint64(200_000)
+		head     = uint64(10)
+	)
+	old := &ChainConfig{BluebirdTime: &bluebird}
+
+	// Rescheduling Bluebird before it activates only affects future blocks
+	future := &ChainConfig{BluebirdTime: &later}
+	require.False(t, IsFeeParamChangeConsensusBreaking(old, future, head, bluebird-1))
+	require.True(t, IsFeeParamChangeConsensusBreaking(old, future, head, bluebird))
+
+	// Adding a floor decay only breaks consensus once Bluebird blocks exist
+	decay := &ChainConfig{BluebirdTime: &bluebird, BluebirdFloorDecayTarget: &target, BluebirdFloorDecayWindow: 100}
+	require.False(t, IsFeeParamChangeConsensusBreaking(old, decay, head, bluebird-1))
+	require.True(t, IsFeeParamChangeConsensusBreaking(old, decay, head, bluebird+1))
+
+	// Overriding the denominator likewise
+	denom := uint64(16)
+	override := &ChainConfig{BluebirdTime: &bluebird, BluebirdBaseFeeChangeDenominatorOverride: &denom}
+	require.False(t, IsFeeParamChangeConsensusBreaking(old, override, head, bluebird-1))
+	require.True(t, IsFeeParamChangeConsensusBreaking(old, override, head, bluebird+1))
+
+	// Unchanged parameters are never breaking
+	require.False(t, IsFeeParamChangeConsensusBreaking(decay, decay, head, later))
+
+	// Optimism parameters only matter once London is active
+	op := &ChainConfig{LondonBlock: new(big.Int).SetUint64(head), Optimism: &OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50}}
+	tweaked := &ChainConfig{LondonBlock: new(big.Int).SetUint64(head), Optimism: &OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 250}}
+	require.False(t, IsFeeParamChangeConsensusBreaking(op, tweaked, head-1, 0))
+	require.True(t, IsFeeParamChangeConsensusBreaking(op, tweaked, head, 0))
+
+	// Rescheduling London likewise
+	moved := &ChainConfig{LondonBlock: new(big.Int).SetUint64(head + 5), Optimism: op.Optimism}
+	require.False(t, IsFeeParamChangeConsensusBreaking(op, moved, head-1, 0))
+	require.True(t, IsFeeParamChangeConsensusBreaking(op, moved, head, 0))
+
+	// Fork reschedules are breaking exactly when CheckCompatible rejects them
+	for _, tt := range []struct {
+		old, new   *ChainConfig
+		head, time uint64
+	}{
+		{old, future, head, bluebird - 1},
+		{old, future, head, bluebird},
+		{op, moved, head - 1, 0},
+		{op, moved, head, 0},
+	} {
+		compatErr := tt.old.CheckCompatible(tt.new, tt.head, tt.time, nil)
+		require.Equal(t, compatErr != nil, IsFeeParamChangeConsensusBreaking(tt.old, tt.new, tt.head, tt.time), "head %d, time %d", tt.head, tt.time)
+	}
+}
+
+func TestBaseFeeParams(t *testing.T) {
+	var (
+		canyon   = uint64(0)
+		bluebird = uint64(1000)
+		denom    = uint64(250)
+	)
+	op := &ChainConfig{
+		CanyonTime:   &canyon,
+		BluebirdTime: &bluebird,
+		Optimism: &OptimismConfig{
+			EIP1559Elasticity:        6,
+			EIP1559Denominator:       50,
+			EIP1559DenominatorCanyon: &denom,
+		},
+	}
+	l1 := &ChainConfig{BluebirdTime: &bluebird}
+
+	for _, tt := range []struct {
+		name   string
+		config *ChainConfig
+		time   uint64
+		want   BaseFeeParams
+	}{
+		{"optimism pre-Bluebird", op, bluebird - 1, BaseFeeParams{6, denom, 0}},
+		{"optimism at Bluebird", op, bluebird, BaseFeeParams{BluebirdElasticityMultiplier, BluebirdBaseFeeChangeDenominator, BluebirdMinBaseFee}},
+		{"optimism post-Bluebird", op, bluebird + 1, BaseFeeParams{BluebirdElasticityMultiplier, BluebirdBaseFeeChangeDenominator, BluebirdMinBaseFee}},
+		{"default pre-Bluebird", l1, bluebird - 1, BaseFeeParams{DefaultElasticityMultiplier, DefaultBaseFeeChangeDenominator, 0}},
+		{"default at Bluebird", l1, bluebird, BaseFeeParams{BluebirdElasticityMultiplier, BluebirdBaseFeeChangeDenominator, BluebirdMinBaseFee}},
+	} {
+		params := tt.config.BaseFeeParamsAt(tt.time)
+		require.Equal(t, tt.want, params, tt.name)
+		require.Equal(t, tt.config.ElasticityMultiplier(tt.time), params.Elasticity, tt.name)
+		require.Equal(t, tt.config.BaseFeeChangeDenominator(tt.time), params.Denominator, tt.name)
+		require.Equal(t, tt.config.MinBaseFee(tt.time), params.MinBaseFee, tt.name)
+	}
+}
+
+func TestBaseFeeParamsInvalidCanyonDenominator(t *testing.T) {
+	var (
+		canyon   = uint64(0)
+		bluebird = uint64(1000)
+	)
+	c := &ChainConfig{
+		CanyonTime:   &canyon,
+		BluebirdTime: &bluebird,
+		Optimism:     &OptimismConfig{EIP1559Elasticity: 6, EIP1559Denominator: 50},
+	}
+	// Only the denominator itself is invalid, not the rest of the parameters
+	require.Equal(t, uint64(6), c.ElasticityMultiplier(bluebird-1))
+	require.Equal(t, uint64(0), c.MinBaseFee(bluebird-1))
+	require.Panics(t, func() { c.BaseFeeChangeDenominator(bluebird - 1) })
+	require.Panics(t, func() { c.BaseFeeParamsAt(bluebird - 1) })
+
+	// Bluebird does not use the Canyon denominator
+	require.Equal(t, BluebirdBaseFeeChangeDenominator, c.BaseFeeChangeDenominator(bluebird))
+	require.Equal(t, BluebirdBaseFeeChangeDenominator, c.BaseFeeParamsAt(bluebird).Denominator)
+}
+
+func TestBluebirdBaseFeeChangeDenominatorOverride(t *testing.T) {
+	var (
+		bluebird = uint64(1000)
+		denom    = uint64(16)
+	)
+	c := &ChainConfig{BluebirdTime: &bluebird}
+	require.Equal(t, BluebirdBaseFeeChangeDenominator, c.BaseFeeChangeDenominator(bluebird))
+
+	c.BluebirdBaseFeeChangeDenominatorOverride = &denom
+	require.Equal(t, uint64(DefaultBaseFeeChangeDenominator), c.BaseFeeChangeDenominator(bluebird-1), "pre-Bluebird")
+	require.Equal(t, denom, c.BaseFeeChangeDenominator(bluebird))
+	require.Equal(t, BluebirdElasticityMultiplier, c.ElasticityMultiplier(bluebird))
+
+	// A zero override is treated as unset
+	zero := uint64(0)
+	c.BluebirdBaseFeeChangeDenominatorOverride = &zero
+	require.Equal(t, BluebirdBaseFeeChangeDenominator, c.BaseFeeChangeDenominator(bluebird))
+}
+
+func TestBluebirdTargetRatio(t *testing.T) {
+	// Moving from an elasticity of 2 to 3 shrinks the target to two thirds
+	if have, want := BluebirdTargetRatio(), big.NewRat(2, 3); have.Cmp(want) != 0 {
+		t.Errorf("target ratio mismatch: have %s, want %s", have, want)
+	}
+	// The ratio must describe the actual targets for a given gas limit
+	gasLimit := uint64(30_000_000)
+	ratio := new(big.Rat).SetFrac64(int64(gasLimit/BluebirdElasticityMultiplier), int64(gasLimit/DefaultElasticityMultiplier))
+	if ratio.Cmp(BluebirdTargetRatio()) != 0 {
+		t.Errorf("target ratio does not match gas targets: have %s, want %s", BluebirdTargetRatio(), ratio)
+	}
+}
+
+func TestBaseFeeChangeDenominatorInfo(t *testing.T) {
+	var (
+		bluebird = uint64(1000)
+		denom    = uint64(16)
+	)
+	c := &ChainConfig{BluebirdTime: &bluebird, BluebirdBaseFeeChangeDenominatorOverride: &denom}
+	for _, tc := range []struct {
+		time  uint64
+		value uint64
+		label string
+	}{
+		{bluebird - 1, DefaultBaseFeeChangeDenominator, "default"},
+		{bluebird, denom, "bluebird"},
+		{bluebird + 1, denom, "bluebird"},
+	} {
+		value, label := c.BaseFeeChangeDenominatorInfo(tc.time)
+		require.Equal(t, tc.value, value, "time %d", tc.time)
+		require.Equal(t, tc.label, label, "time %d", tc.time)
+	}
+}
+
+func TestBluebirdTimeJSON(t *testing.T) {
+	bluebird := uint64(1000)
+	for _, tc := range []struct {
+		name string
+		time *uint64
+	}{
+		{"unset", nil},
+		{"genesis", new(uint64)},
+		{"scheduled", &bluebird},
+	} {
+		enc, err := json.Marshal(&ChainConfig{ChainID: big.NewInt(1), BluebirdTime: tc.time})
+		require.NoError(t, err, tc.name)
+
+		var fields map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal(enc, &fields), tc.name)
+		_, ok := fields["bluebirdTime"]
+		require.Equal(t, tc.time != nil, ok, "%s: bluebirdTime presence in %s", tc.name, enc)
+
+		var dec ChainConfig
+		require.NoError(t, json.Unmarshal(enc, &dec), tc.name)
+		if tc.time == nil {
+			require.Nil(t, dec.BluebirdTime, tc.name)
+		} else {
+			require.NotNil(t, dec.BluebirdTime, tc.name)
+			require.Equal(t, *tc.time, *dec.BluebirdTime, tc.name)
+		}
+	}
+}
+
+func TestCheckConfigForkOrderBluebird(t *testing.T) {
+	var (
+		genesis  = uint64(0)
+		bluebird = uint64(1000)
+	)
+	for _, tc := range []struct {
+		name     string
+		london   *big.Int
+		bluebird *uint64
+		wantErr  bool
+	}{
+		{"no bluebird", big.NewInt(10), nil, false},
+		{"london at genesis, bluebird at genesis", big.NewInt(0), &genesis, false},
+		{"london at genesis, bluebird later", big.NewInt(0), &bluebird, false},
+		{"london later, bluebird later", big.NewInt(10), &bluebird, false},
+		{"london later, bluebird at genesis", big.NewInt(10), &genesis, true},
+		{"no london, bluebird later", nil, &bluebird, true},
+	} {
+		// Drop the forks after London, so that only its ordering against Bluebird
+		// is under test
+		c := *TestChainConfig
+		c.ArrowGlacierBlock, c.GrayGlacierBlock, c.MergeNetsplitBlock = nil, nil, nil
+		c.ShanghaiTime, c.CancunTime, c.PragueTime, c.VerkleTime = nil, nil, nil, nil
+		c.LondonBlock = tc.london
+		c.BluebirdTime = tc.bluebird
+		err := c.CheckConfigForkOrder()
+		require.Equal(t, tc.wantErr, err != nil, "%s: have error %v", tc.name, err)
+	}
+}